@@ -0,0 +1,151 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/SMerrony/tello"
+	"github.com/nsf/termbox-go"
+)
+
+const (
+	battSamplePeriod = time.Second
+	battWindowLen    = 180 // samples kept for the trend, i.e. 3 minutes
+	battMinSamples   = 20  // don't guess until we have this many samples
+)
+
+type battSample struct {
+	t   time.Time
+	pct float64
+}
+
+// battEstimator keeps a rolling window of battery percentages and estimates
+// the time left until empty via a least-squares fit of percentage vs. time
+type battEstimator struct {
+	samples []battSample
+}
+
+var battEst battEstimator
+
+func (be *battEstimator) reset() {
+	be.samples = be.samples[:0]
+}
+
+func (be *battEstimator) addSample(now time.Time, pct int8) {
+	if n := len(be.samples); n > 0 && now.Sub(be.samples[n-1].t) < battSamplePeriod {
+		return
+	}
+	be.samples = append(be.samples, battSample{now, float64(pct)})
+	if len(be.samples) > battWindowLen {
+		be.samples = be.samples[len(be.samples)-battWindowLen:]
+	}
+}
+
+// estimate returns the predicted time until the battery reaches zero,
+// ok is false if there is not enough data or the battery is not draining
+func (be *battEstimator) estimate(now time.Time) (left time.Duration, ok bool) {
+	n := float64(len(be.samples))
+	if len(be.samples) < battMinSamples {
+		return 0, false
+	}
+	t0 := be.samples[0].t
+	var sumT, sumP, sumTT, sumTP float64
+	for _, s := range be.samples {
+		t := s.t.Sub(t0).Seconds()
+		sumT += t
+		sumP += s.pct
+		sumTT += t * t
+		sumTP += t * s.pct
+	}
+	denom := n*sumTT - sumT*sumT
+	if denom == 0 {
+		return 0, false
+	}
+	slope := (n*sumTP - sumT*sumP) / denom // %/sec
+	if slope >= 0 {
+		return 0, false
+	}
+	intercept := (sumP - slope*sumT) / n
+	pctNow := intercept + slope*now.Sub(t0).Seconds()
+	if pctNow <= 0 {
+		return 0, true
+	}
+	return time.Duration(-pctNow / slope * float64(time.Second)), true
+}
+
+func formatMinSec(d time.Duration) string {
+	secs := int(d.Seconds())
+	return fmt.Sprintf("%02d:%02d", secs/60, secs%60)
+}
+
+// battStatus combines the drone's three battery flags into one, the raw flags
+// are still on the debug page.  The precedence is:
+//
+//	BatteryCritical           CRITICAL - land now, the drone will soon do it anyway
+//	BatteryLow                LOW      - below the low battery threshold
+//	BatteryState              LOW      - the drone is flagging a battery problem
+//	none of them              OK
+func battStatus(fd tello.FlightData) (status string, fg termbox.Attribute) {
+	switch {
+	case fd.BatteryCritical:
+		return "CRITICAL", th.Bad | termbox.AttrBold
+	case fd.BatteryLow, fd.BatteryState:
+		return "LOW", th.Caution | termbox.AttrBold
+	}
+	return "OK", th.Good
+}
+
+const telloCells = 1 // the Tello battery is a single LiPo cell
+
+// typical resting LiPo cell voltage at 0%, 10% ... 100%
+var lipoRestVolts = [...]float64{3.27, 3.69, 3.73, 3.77, 3.79, 3.82, 3.87, 3.93, 4.00, 4.08, 4.20}
+
+// restVolts interpolates the voltage a healthy cell would show at pct
+func restVolts(pct int8) float64 {
+	p := math.Max(0, math.Min(100, float64(pct))) / 10
+	i := int(p)
+	if i >= len(lipoRestVolts)-1 {
+		return lipoRestVolts[len(lipoRestVolts)-1]
+	}
+	return lipoRestVolts[i] + (p-float64(i))*(lipoRestVolts[i+1]-lipoRestVolts[i])
+}
+
+// battHealth compares the cell voltage with what the reported percentage
+// suggests, a tired battery sags well below it under load
+func battHealth(cellVolts float64, pct int8, flying bool) string {
+	sag := restVolts(pct) - cellVolts
+	if !flying {
+		sag *= 2 // there should be hardly any sag at rest
+	}
+	switch {
+	case sag < 0.15:
+		return "Good"
+	case sag < 0.3:
+		return "Sag"
+	default:
+		return "Weak"
+	}
+}
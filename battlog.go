@@ -0,0 +1,111 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const battLogFileName = "telloterm_batteries.json"
+
+// battStats is the usage record kept for each -battid
+type battStats struct {
+	Flights  int
+	Minutes  float64
+	LastUsed time.Time
+}
+
+var (
+	battLogMu   sync.Mutex
+	battTakeoff time.Time // start of the current flight, guarded by fieldsMu
+)
+
+// battLogPath puts the battery records beside the config file, or in $HOME if there isn't one
+func battLogPath() string {
+	dir := "."
+	if cfg.path != "" {
+		dir = filepath.Dir(cfg.path)
+	} else if home, err := os.UserHomeDir(); err == nil {
+		dir = home
+	}
+	return filepath.Join(dir, battLogFileName)
+}
+
+// loadBattLog returns every battery's record, a missing file is not an error
+func loadBattLog() (map[string]*battStats, error) {
+	bl := map[string]*battStats{}
+	buf, err := ioutil.ReadFile(battLogPath())
+	if os.IsNotExist(err) {
+		return bl, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return bl, json.Unmarshal(buf, &bl)
+}
+
+// battSummary describes the -battid battery's history
+func battSummary() string {
+	bl, err := loadBattLog()
+	if err != nil {
+		return fmt.Sprintf("Cannot read battery records - %v", err)
+	}
+	st, ok := bl[*battIDFlag]
+	if !ok {
+		return fmt.Sprintf("Battery %s: first flight", *battIDFlag)
+	}
+	return fmt.Sprintf("Battery %s: %d flights, %.0f minutes, last used %s",
+		*battIDFlag, st.Flights, st.Minutes, st.LastUsed.Format("Jan 2"))
+}
+
+// recordBattFlight adds a flight of the given length to the -battid battery's record
+func recordBattFlight(flight time.Duration) {
+	battLogMu.Lock()
+	defer battLogMu.Unlock()
+	bl, err := loadBattLog()
+	if err != nil {
+		logError("Cannot read battery records - %v", err)
+		return
+	}
+	st, ok := bl[*battIDFlag]
+	if !ok {
+		st = &battStats{}
+		bl[*battIDFlag] = st
+	}
+	st.Flights++
+	st.Minutes += flight.Minutes()
+	st.LastUsed = time.Now()
+	buf, err := json.MarshalIndent(bl, "", "  ")
+	if err == nil {
+		err = ioutil.WriteFile(battLogPath(), buf, 0644)
+	}
+	if err != nil {
+		logError("Cannot save battery records - %v", err)
+	}
+}
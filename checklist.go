@@ -0,0 +1,105 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/SMerrony/tello"
+	"github.com/nsf/termbox-go"
+)
+
+// With -checklist a takeoff is refused unless the battery, WiFi signal and
+// temperature are all fine and the flight data is up to date.  Asking to take
+// off again within checkOverrideTime goes ahead anyway.
+
+const (
+	checkMinBatt      = 30 // percent
+	checkMinWiFi      = 40 // percent
+	checkOverrideTime = 3 * time.Second
+)
+
+var checkRefused time.Time // when a takeoff was last refused, guarded by flightCmdMu
+
+// preflightProblems lists whatever would stop a takeoff, lastData is when the
+// flight data last changed
+func preflightProblems(fd tello.FlightData, lastData, now time.Time) (problems []string) {
+	if lastData.IsZero() || now.Sub(lastData) > linkTimeout {
+		return []string{"no flight data"}
+	}
+	if fd.BatteryPercentage < checkMinBatt {
+		problems = append(problems, fmt.Sprintf("battery %d%%", fd.BatteryPercentage))
+	}
+	if fd.WifiStrength < checkMinWiFi {
+		problems = append(problems, fmt.Sprintf("WiFi %d%%", fd.WifiStrength))
+	}
+	if fd.IMU.Temperature > maxTempC {
+		problems = append(problems, fmt.Sprintf("temp %dC", fd.IMU.Temperature))
+	}
+	return problems
+}
+
+// showChecklist is called from updateFields with fieldsMu held
+func showChecklist(fd tello.FlightData, now time.Time) {
+	if problems := preflightProblems(fd, now, now); len(problems) > 0 {
+		fields[fChecklist].value = "NOT READY: " + strings.Join(problems, ", ")
+		fields[fChecklist].fg = th.Bad | termbox.AttrBold
+	} else {
+		fields[fChecklist].value = "READY"
+		fields[fChecklist].fg = th.Good
+	}
+}
+
+// checkPreflight returns an error if a takeoff should not go ahead, also
+// showing it, unless the user is overriding an earlier refusal
+func checkPreflight() error {
+	if !*checklistFlag {
+		return nil
+	}
+	fieldsMu.RLock()
+	fd, last := prevFd, lastFdTime
+	fieldsMu.RUnlock()
+	now := time.Now()
+	problems := preflightProblems(fd, last, now)
+	if len(problems) == 0 || fd.Flying {
+		return nil
+	}
+	flightCmdMu.Lock()
+	override := now.Sub(checkRefused) < checkOverrideTime
+	if override {
+		checkRefused = time.Time{}
+	} else {
+		checkRefused = now
+	}
+	flightCmdMu.Unlock()
+	if override {
+		logWarn("Pre-flight checks overridden - %s", strings.Join(problems, ", "))
+		return nil
+	}
+	err := fmt.Errorf("not ready to take off - %s, take off again within %v to override",
+		strings.Join(problems, ", "), checkOverrideTime)
+	showMessage(err.Error())
+	return err
+}
@@ -0,0 +1,236 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SMerrony/tello"
+)
+
+// command is a text command which may be used in scripts
+type command struct {
+	args  int    // number of arguments required
+	usage string // shown in the command list
+	fn    func(args []string) error
+}
+
+// remoteCmdMu runs the commands of -rpcsock and -grpc clients one at a time, in
+// the order they arrive.  The keyboard and joystick do not take it so that the
+// pilot can always step in, e.g. land while a client's flyto is in progress.
+var remoteCmdMu sync.Mutex
+
+var commands = map[string]command{
+	"takeoff":      {0, "takeoff", func([]string) error { return takeOff() }},
+	"throwtakeoff": {0, "throwtakeoff", func([]string) error { return throwTakeOff() }},
+	"land":         {0, "land", func([]string) error { land(); return nil }},
+	"palmland":     {0, "palmland", func([]string) error { palmLand(); return nil }},
+	"hover":        {0, "hover", func([]string) error { drone.Hover(); return nil }},
+	"bounce":       {0, "bounce", func([]string) error { toggleBounce(); return nil }},
+	"photo":        {0, "photo", func([]string) error { drone.TakePicture(); return nil }},
+	"fast":         {0, "fast", func([]string) error { setFastMode(true); return nil }},
+	"slow":         {0, "slow", func([]string) error { setFastMode(false); return nil }},
+	"sethome":      {0, "sethome", func([]string) error { setHome(); return nil }},
+	"home":         {0, "home", func([]string) error { return goHome() }},
+	"homeland":     {0, "homeland", func([]string) error { return startReturnAndLand() }},
+	"360":          {0, "360", func([]string) error { startSmartVideo(tello.Sv360); return nil }},
+	"up":           {1, "up <pct>", pctCmd(telloDrone.Up)},
+	"down":         {1, "down <pct>", pctCmd(telloDrone.Down)},
+	"left":         {1, "left <pct>", pctCmd(telloDrone.Left)},
+	"right":        {1, "right <pct>", pctCmd(telloDrone.Right)},
+	"forward":      {1, "forward <pct>", pctCmd(telloDrone.Forward)},
+	"backward":     {1, "backward <pct>", pctCmd(telloDrone.Backward)},
+	"cw":           {1, "cw <pct>", pctCmd(telloDrone.TurnRight)},
+	"ccw":          {1, "ccw <pct>", pctCmd(telloDrone.TurnLeft)},
+	"flip":         {1, "flip f|b|l|r", flipCmd},
+	"flyto":        {2, "flyto <x> <y>", flyToCmd},
+	"wait":         {1, "wait <seconds>", waitCmd},
+	"lowbatt":      {1, "lowbatt <pct>", lowBattCmd},
+}
+
+// runCommand parses and executes a single line such as "flyto 1 0"
+func runCommand(line string) error {
+	cmd, args, err := parseCommand(line)
+	if err != nil || cmd.fn == nil {
+		return err
+	}
+	logDebug("Command <%s>", line)
+	if err := cmd.fn(args); err != nil {
+		logDebug("Command <%s> failed - %v", line, err)
+		return err
+	}
+	return nil
+}
+
+// parseCommand finds the command for a line and checks its number of arguments,
+// a blank line gives an empty command
+func parseCommand(line string) (cmd command, args []string, err error) {
+	words := strings.Fields(line)
+	if len(words) == 0 {
+		return cmd, nil, nil
+	}
+	cmd, ok := commands[strings.ToLower(words[0])]
+	if !ok {
+		return cmd, nil, fmt.Errorf("unknown command <%s>", words[0])
+	}
+	if len(words)-1 != cmd.args {
+		return cmd, nil, fmt.Errorf("usage: %s", cmd.usage)
+	}
+	return cmd, words[1:], nil
+}
+
+// runScript executes commands line by line until EOF, "quit" or an error.
+// Blank lines and lines starting with '#' are ignored.
+func runScript(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if line == "quit" {
+			return nil
+		}
+		if err := runCommand(line); err != nil {
+			return fmt.Errorf("line %d: %v", lineNo, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// runStartCommands runs each command in the -onstart file, reporting any
+// that fail but carrying on with the rest
+func runStartCommands(path string) {
+	report := func(msg string) {
+		logWarn("%s", msg)
+		if !*headlessFlag {
+			showMessage(msg)
+		}
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		report(fmt.Sprintf("Cannot run -onstart commands - %v", err))
+		return
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := runCommand(line); err != nil {
+			report(fmt.Sprintf("%s line %d: %v", path, lineNo, err))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		report(fmt.Sprintf("Cannot read %s - %v", path, err))
+	}
+}
+
+// pctCmd makes a command from a movement method, it is called on drone when run
+func pctCmd(move func(telloDrone, int)) func([]string) error {
+	return func(args []string) error {
+		pct, err := strconv.Atoi(args[0])
+		if err != nil || pct < 0 || pct > 100 {
+			return fmt.Errorf("percentage must be 0-100, not <%s>", args[0])
+		}
+		move(drone, pct)
+		return nil
+	}
+}
+
+func flipCmd(args []string) error {
+	switch args[0] {
+	case "f":
+		drone.ForwardFlip()
+	case "b":
+		drone.BackFlip()
+	case "l":
+		drone.LeftFlip()
+	case "r":
+		drone.RightFlip()
+	default:
+		return fmt.Errorf("flip direction must be one of f, b, l or r")
+	}
+	return nil
+}
+
+func flyToCmd(args []string) error {
+	x, err := strconv.ParseFloat(args[0], 32)
+	if err != nil {
+		return fmt.Errorf("bad X coordinate <%s>", args[0])
+	}
+	y, err := strconv.ParseFloat(args[1], 32)
+	if err != nil {
+		return fmt.Errorf("bad Y coordinate <%s>", args[1])
+	}
+	return flyTo(float32(x), float32(y))
+}
+
+// flyTo starts an automatic flight to the given MVO position relative to home,
+// the target is shown on the map until the drone gets there or gives up
+func flyTo(x, y float32) error {
+	if !drone.IsHomeSet() {
+		return fmt.Errorf("home is not set")
+	}
+	done, err := drone.AutoFlyToXY(x, y)
+	if err != nil {
+		return err
+	}
+	target := setFlyTarget(x, y)
+	go func() {
+		arrived := <-done
+		if clearFlyTarget(target) && arrived {
+			showMessage(fmt.Sprintf("Arrived at %.1f, %.1f", x, y))
+			logInfo("Arrived at %.1f, %.1f", x, y)
+		}
+	}()
+	return nil
+}
+
+func waitCmd(args []string) error {
+	secs, err := strconv.ParseFloat(args[0], 64)
+	if err != nil || secs < 0 {
+		return fmt.Errorf("bad number of seconds <%s>", args[0])
+	}
+	time.Sleep(time.Duration(secs * float64(time.Second)))
+	return nil
+}
+
+func lowBattCmd(args []string) error {
+	pct, err := strconv.Atoi(args[0])
+	if err != nil || pct < 0 || pct > 100 {
+		return fmt.Errorf("percentage must be 0-100, not <%s>", args[0])
+	}
+	drone.SetLowBatteryThreshold(uint8(pct))
+	drone.GetLowBatteryThreshold() // so that the display catches up
+	return nil
+}
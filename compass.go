@@ -0,0 +1,104 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"math"
+
+	"github.com/nsf/termbox-go"
+)
+
+const (
+	compassX      = 10 // left edge of the tape
+	compassY      = 5
+	compassW      = 61 // odd, so there is a centre column
+	compassDegCol = 3  // degrees per column
+)
+
+// normDeg returns d in the range [0, 360)
+func normDeg(d float64) float64 {
+	d = math.Mod(d, 360)
+	if d < 0 {
+		d += 360
+	}
+	return d
+}
+
+// bearingToHome returns the heading from the drone to home in the same sense as
+// the IMU yaw, with 0 along the MVO X axis and angles increasing clockwise
+func bearingToHome() (deg float64, ok bool) {
+	homeMu.Lock()
+	defer homeMu.Unlock()
+	if home == nil {
+		return 0, false
+	}
+	dx := float64(home.X - prevFd.MVO.PositionX)
+	dy := float64(home.Y - prevFd.MVO.PositionY)
+	if math.Hypot(dx, dy) < trailMinStep {
+		return 0, false // already home
+	}
+	return normDeg(math.Atan2(dy, dx) * 180 / math.Pi), true
+}
+
+// compassMark gives the character for the compass tape column covering [from, from+compassDegCol)
+func compassMark(from float64) rune {
+	for d := math.Ceil(from); d < from+compassDegCol; d++ {
+		switch deg := int(normDeg(d)); {
+		case deg == 0:
+			return 'N'
+		case deg == 90:
+			return 'E'
+		case deg == 180:
+			return 'S'
+		case deg == 270:
+			return 'W'
+		case deg%45 == 0:
+			return '+'
+		case deg%15 == 0:
+			return '|'
+		}
+	}
+	return '-'
+}
+
+// drawCompass renders a scrolling heading tape centred on the current yaw,
+// with the bearing to home marked 'H', fieldsMu must be read-locked
+func drawCompass() {
+	yaw := float64(prevFd.IMU.Yaw)
+	homeBrg, homeOK := bearingToHome()
+	centre := compassW / 2
+	for i := 0; i < compassW; i++ {
+		from := yaw + float64(i-centre)*compassDegCol - compassDegCol/2.0
+		ch := compassMark(from)
+		fg := th.Value
+		if homeOK {
+			if off := normDeg(homeBrg - from); off < compassDegCol {
+				ch, fg = 'H', th.Caution|termbox.AttrBold
+			}
+		}
+		if i == centre {
+			fg |= termbox.AttrReverse
+		}
+		setCell(compassX+i, compassY, ch, fg, th.Background)
+	}
+}
@@ -0,0 +1,101 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+const configFileName = "telloterm.json"
+
+// config holds the contents of the optional configuration file.
+// Any top-level key which matches a command-line flag name (e.g. "jsid", "jstype")
+// becomes that flag's default, so flags given on the command line still win.
+type config struct {
+	path  string                     // where the config was loaded from, "" if none
+	flags map[string]json.RawMessage // every top-level key, flag or not
+}
+
+var cfg config
+
+// findConfig returns the first config file found in the working directory or $HOME
+func findConfig() string {
+	dirs := []string{"."}
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, home)
+	}
+	for _, dir := range dirs {
+		path := filepath.Join(dir, configFileName)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// loadConfig reads the config file, if there is one, and seeds the flag defaults.
+// It must be called before flag.Parse().
+func loadConfig() {
+	path := findConfig()
+	if path == "" {
+		return
+	}
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Fatalf("Cannot read config file %s - %v", path, err)
+	}
+	if err = json.Unmarshal(buf, &cfg.flags); err != nil {
+		log.Fatalf("Cannot parse config file %s - %v", path, err)
+	}
+	cfg.path = path
+	for name, raw := range cfg.flags {
+		if flag.Lookup(name) == nil {
+			continue
+		}
+		var val interface{}
+		if err = json.Unmarshal(raw, &val); err != nil {
+			log.Fatalf("Bad value for %s in config file %s - %v", name, path, err)
+		}
+		if err = flag.Set(name, fmt.Sprint(val)); err != nil {
+			log.Fatalf("Bad value for %s in config file %s - %v", name, path, err)
+		}
+	}
+}
+
+// get decodes the config entry for key into v, it returns false if there is no such entry
+func (c *config) get(key string, v interface{}) bool {
+	raw, ok := c.flags[key]
+	if !ok {
+		return false
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		log.Fatalf("Bad value for %s in config file %s - %v", key, c.path, err)
+	}
+	return true
+}
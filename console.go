@@ -0,0 +1,134 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nsf/termbox-go"
+)
+
+// runConsole reads a command line on the message line, opened with ':',
+// and runs it as a script command.  Enter runs it, Esc abandons it.
+func runConsole() {
+	var line []rune
+	for {
+		showMessage(":" + string(line) + "_")
+		ev := termbox.PollEvent()
+		switch {
+		case ev.Type == termbox.EventInterrupt:
+			return
+		case ev.Type != termbox.EventKey:
+			continue
+		case ev.Key == termbox.KeyEsc:
+			showMessage("")
+			return
+		case ev.Key == termbox.KeyEnter:
+			go consoleCommand(string(line)) // "wait" etc. mustn't hold up the keyboard
+			return
+		case ev.Key == termbox.KeyBackspace || ev.Key == termbox.KeyBackspace2:
+			if len(line) > 0 {
+				line = line[:len(line)-1]
+			}
+		case ev.Key == termbox.KeySpace:
+			line = append(line, ' ')
+		case ev.Ch != 0:
+			line = append(line, ev.Ch)
+		}
+	}
+}
+
+// helpNote ends the console help, the drone's max height and SSID are only
+// shown as the tello package has no calls to set them
+const helpNote = "(maxheight and ssid are read-only, the tello package cannot set them)"
+
+// helpPages splits the command names into pages that fit in width with
+// room for the "(1/2) " and " - help 2 for more" that consoleCommand adds
+func helpPages(width int) []string {
+	var names []string
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	names = append(names, helpNote)
+	width -= len("(9/9) ") + len(" - help 9 for more")
+	var pages []string
+	page := ""
+	for _, name := range names {
+		if page != "" && len(page)+1+len(name) > width {
+			pages = append(pages, page)
+			page = ""
+		}
+		if page != "" {
+			page += " "
+		}
+		page += name
+	}
+	return append(pages, page)
+}
+
+// consoleHelp shows page n (from 1) of the command list on the message line
+func consoleHelp(n int) {
+	fieldsMu.RLock()
+	width := fields[fMessage].w
+	fieldsMu.RUnlock()
+	pages := helpPages(width)
+	if n < 1 || n > len(pages) {
+		showMessage(fmt.Sprintf("help: there are %d pages", len(pages)))
+		return
+	}
+	msg := fmt.Sprintf("(%d/%d) %s", n, len(pages), pages[n-1])
+	if n < len(pages) {
+		msg += fmt.Sprintf(" - help %d for more", n+1)
+	}
+	showMessage(msg)
+}
+
+func consoleCommand(line string) {
+	line = strings.TrimSpace(line)
+	words := strings.Fields(line)
+	switch {
+	case len(words) == 0:
+		showMessage("")
+		return
+	case words[0] == "help" || words[0] == "?":
+		n := 1
+		if len(words) > 1 {
+			var err error
+			if n, err = strconv.Atoi(words[1]); err != nil {
+				showMessage(fmt.Sprintf("help: bad page number <%s>", words[1]))
+				return
+			}
+		}
+		consoleHelp(n)
+		return
+	}
+	if err := runCommand(line); err != nil {
+		showMessage(fmt.Sprintf("%s: %v", line, err))
+		return
+	}
+	showMessage(fmt.Sprintf("%s: OK", line))
+}
@@ -0,0 +1,82 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"time"
+
+	"github.com/SMerrony/tello"
+)
+
+// telloDrone is the part of *tello.Tello used by telloterm,
+// it lets -sim stand in a simulated drone for the real one
+type telloDrone interface {
+	ControlConnectDefault() error
+	StreamFlightData(asAvailable bool, periodMs time.Duration) (<-chan tello.FlightData, error)
+	VideoConnectDefault() (<-chan []byte, error)
+	VideoDisconnect()
+	GetVideoSpsPps()
+	SetVideoNormal()
+	SetVideoWide()
+	StartSmartVideo(cmd tello.SvCmd)
+
+	GetLowBatteryThreshold()
+	SetLowBatteryThreshold(thr uint8)
+	GetMaxHeight()
+	GetSSID()
+	GetVersion()
+
+	TakeOff()
+	ThrowTakeOff()
+	Land()
+	PalmLand()
+	Bounce()
+	Hover()
+	SetFastMode()
+	SetSlowMode()
+
+	Forward(pct int)
+	Backward(pct int)
+	Left(pct int)
+	Right(pct int)
+	Up(pct int)
+	Down(pct int)
+	TurnLeft(pct int)
+	TurnRight(pct int)
+
+	ForwardFlip()
+	BackFlip()
+	LeftFlip()
+	RightFlip()
+
+	TakePicture() (ok bool)
+	NumPics() (np int)
+	SaveAllPics(prefix string) (np int, err error)
+
+	StartStickListener() (sendChan chan<- tello.StickMessage, err error)
+
+	SetHome()
+	IsHomeSet() bool
+	AutoFlyToXY(x, y float32) (done chan bool, err error)
+	CancelAutoFlyToXY()
+}
@@ -0,0 +1,170 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SMerrony/tello"
+)
+
+// flightLogger is implemented by each of the -fdlog output formats
+type flightLogger interface {
+	WriteHeader() error
+	WriteRecord(fd tello.FlightData) error
+	Close() error
+}
+
+const fdLogTimeFmt = "15:04:05.000"
+
+// checkLogTime makes sure -logtime names a known format
+func checkLogTime() error {
+	switch *logTimeFlag {
+	case "clock", "rfc3339", "epoch":
+		return nil
+	}
+	return fmt.Errorf("unknown -logtime format <%s>", *logTimeFlag)
+}
+
+// logTime formats t for the Time column of the flight, joystick and text logs as chosen by -logtime
+func logTime(t time.Time) string {
+	switch *logTimeFlag {
+	case "rfc3339":
+		return t.Format(time.RFC3339Nano)
+	case "epoch":
+		return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10)
+	}
+	return t.Format(fdLogTimeFmt)
+}
+
+// fdPitchRoll derives the attitude from the IMU quaternion as the flight data has no pitch or roll
+func fdPitchRoll(fd tello.FlightData) (pitch, roll int) {
+	pitch, roll, _ = tello.QuatToEulerDeg(fd.IMU.QuaternionX, fd.IMU.QuaternionY, fd.IMU.QuaternionZ, fd.IMU.QuaternionW)
+	return pitch, roll
+}
+
+// newFlightLogger creates the log file in the format given by -fdlogfmt,
+// or if that is empty, by the file's extension
+func newFlightLogger(path, format string) (flightLogger, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".json", ".jsonl":
+			format = "json"
+		default:
+			format = "csv"
+		}
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case "csv":
+		return &csvLogger{f: f, w: csv.NewWriter(f)}, nil
+	case "json":
+		return &jsonLogger{f: f, enc: json.NewEncoder(f)}, nil
+	}
+	f.Close()
+	return nil, fmt.Errorf("unknown flight log format <%s>", format)
+}
+
+type csvLogger struct {
+	f *os.File
+	w *csv.Writer
+}
+
+func (l *csvLogger) WriteHeader() error {
+	return l.w.Write([]string{"Time", "X", "Y", "Z", "Yaw", "Pitch", "Roll", "FDHeight", "VideoFrame"})
+}
+
+func (l *csvLogger) WriteRecord(fd tello.FlightData) error {
+	pitch, roll := fdPitchRoll(fd)
+	vf := "" // empty unless the video is being recorded
+	if frame, ok := videoFrame(); ok {
+		vf = strconv.FormatInt(frame, 10)
+	}
+	return l.w.Write([]string{logTime(time.Now()), fmt.Sprintf("%f", fd.MVO.PositionX),
+		fmt.Sprintf("%f", fd.MVO.PositionY), fmt.Sprintf("%f", fd.MVO.PositionZ),
+		fmt.Sprintf("%d", fd.IMU.Yaw), fmt.Sprintf("%d", pitch), fmt.Sprintf("%d", roll),
+		fmt.Sprintf("%.1f", float32(fd.Height)/10), vf})
+}
+
+func (l *csvLogger) Close() error {
+	l.w.Flush()
+	if err := l.w.Error(); err != nil {
+		l.f.Close()
+		return err
+	}
+	return l.f.Close()
+}
+
+// jsonLogger writes one JSON object per line
+type jsonLogger struct {
+	f   *os.File
+	enc *json.Encoder
+}
+
+type jsonLogRecord struct {
+	Time       string  `json:"time"`
+	X          float32 `json:"x"`
+	Y          float32 `json:"y"`
+	Z          float32 `json:"z"`
+	Yaw        int16   `json:"yaw"`
+	Pitch      int     `json:"pitch"`
+	Roll       int     `json:"roll"`
+	FDHeight   float32 `json:"fdheight"`
+	VideoFrame *int64  `json:"videoframe,omitempty"` // only while the video is being recorded
+}
+
+// WriteHeader does nothing as every JSON record is self-describing
+func (l *jsonLogger) WriteHeader() error { return nil }
+
+func (l *jsonLogger) WriteRecord(fd tello.FlightData) error {
+	pitch, roll := fdPitchRoll(fd)
+	var vf *int64
+	if frame, ok := videoFrame(); ok {
+		vf = &frame
+	}
+	return l.enc.Encode(jsonLogRecord{
+		Time:       logTime(time.Now()),
+		X:          fd.MVO.PositionX,
+		Y:          fd.MVO.PositionY,
+		Z:          fd.MVO.PositionZ,
+		Yaw:        fd.IMU.Yaw,
+		Pitch:      pitch,
+		Roll:       roll,
+		FDHeight:   float32(fd.Height) / 10,
+		VideoFrame: vf,
+	})
+}
+
+func (l *jsonLogger) Close() error {
+	return l.f.Close()
+}
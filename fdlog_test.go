@@ -0,0 +1,165 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/SMerrony/tello"
+)
+
+func testFlightData(x, y, z float32, yaw int16, height int16) tello.FlightData {
+	var fd tello.FlightData
+	fd.MVO.PositionX, fd.MVO.PositionY, fd.MVO.PositionZ = x, y, z
+	fd.IMU.Yaw = yaw
+	fd.IMU.QuaternionW = 1 // level
+	fd.Height = height
+	return fd
+}
+
+func TestFlightLoggers(t *testing.T) {
+	recs := []tello.FlightData{
+		testFlightData(0, 0, 0, 0, 0),
+		testFlightData(1.5, -2.25, 0.5, 90, 12),
+		testFlightData(-3, 4, -1.75, -179, 105),
+	}
+	dir, err := ioutil.TempDir("", "fdlog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		file, format string
+		read         func(t *testing.T, path string) []jsonLogRecord
+	}{
+		{"flight.csv", "", readCSVLog},
+		{"flight.json", "", readJSONLog},
+		{"flight.log", "json", readJSONLog},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(dir, tt.file)
+		l, err := newFlightLogger(path, tt.format)
+		if err != nil {
+			t.Fatalf("%s: newFlightLogger - %v", tt.file, err)
+		}
+		if err = l.WriteHeader(); err != nil {
+			t.Fatalf("%s: WriteHeader - %v", tt.file, err)
+		}
+		for _, fd := range recs {
+			if err = l.WriteRecord(fd); err != nil {
+				t.Fatalf("%s: WriteRecord - %v", tt.file, err)
+			}
+		}
+		if err = l.Close(); err != nil {
+			t.Fatalf("%s: Close - %v", tt.file, err)
+		}
+		got := tt.read(t, path)
+		if len(got) != len(recs) {
+			t.Fatalf("%s: got %d records, want %d", tt.file, len(got), len(recs))
+		}
+		for i, fd := range recs {
+			g := got[i]
+			if g.Time == "" || g.X != fd.MVO.PositionX || g.Y != fd.MVO.PositionY || g.Z != fd.MVO.PositionZ ||
+				g.Yaw != fd.IMU.Yaw || g.Pitch != 0 || g.Roll != 0 || g.FDHeight != float32(fd.Height)/10 ||
+				g.VideoFrame != nil {
+				t.Errorf("%s: record %d read back as %+v", tt.file, i, g)
+			}
+		}
+	}
+}
+
+// readCSVLog parses a CSV flight log back into records, checking the header
+func readCSVLog(t *testing.T, path string) []jsonLogRecord {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+	if len(rows) == 0 || rows[0][0] != "Time" || rows[0][8] != "VideoFrame" {
+		t.Fatalf("%s: bad header %v", path, rows)
+	}
+	var recs []jsonLogRecord
+	for _, row := range rows[1:] {
+		var rec jsonLogRecord
+		rec.Time = row[0]
+		rec.X = parseFloat32(t, row[1])
+		rec.Y = parseFloat32(t, row[2])
+		rec.Z = parseFloat32(t, row[3])
+		rec.Yaw = int16(parseInt(t, row[4]))
+		rec.Pitch = parseInt(t, row[5])
+		rec.Roll = parseInt(t, row[6])
+		rec.FDHeight = parseFloat32(t, row[7])
+		if row[8] != "" {
+			vf := int64(parseInt(t, row[8]))
+			rec.VideoFrame = &vf
+		}
+		recs = append(recs, rec)
+	}
+	return recs
+}
+
+// readJSONLog parses a JSON lines flight log back into records
+func readJSONLog(t *testing.T, path string) []jsonLogRecord {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var recs []jsonLogRecord
+	dec := json.NewDecoder(f)
+	for dec.More() {
+		var rec jsonLogRecord
+		if err = dec.Decode(&rec); err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		recs = append(recs, rec)
+	}
+	return recs
+}
+
+func parseFloat32(t *testing.T, s string) float32 {
+	v, err := strconv.ParseFloat(s, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return float32(v)
+}
+
+func parseInt(t *testing.T, s string) int {
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
@@ -0,0 +1,111 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/SMerrony/tello"
+	termbox "github.com/nsf/termbox-go"
+)
+
+// The flight state combines the OnGround, Hovering and Flying flags with the
+// takeoff and land commands we have sent into a single easily read label.
+
+const (
+	takeOffStateTime = 5 * time.Second  // how long after takeoff we show TAKING OFF
+	landStateTime    = 15 * time.Second // give up showing LANDING after this
+)
+
+var (
+	flightCmdMu sync.Mutex
+	takeOffCmd  time.Time // when we last asked the drone to take off
+	landCmd     time.Time // when we last asked the drone to land
+	flyingSince time.Time // when the drone last reported it was flying, guarded by fieldsMu
+)
+
+func noteTakeOff() {
+	if *readOnlyFlag { // nothing was sent
+		return
+	}
+	flightCmdMu.Lock()
+	takeOffCmd = time.Now()
+	flightCmdMu.Unlock()
+	armTakeOffHeight()
+}
+
+func noteLand() {
+	if *readOnlyFlag { // nothing was sent
+		return
+	}
+	flightCmdMu.Lock()
+	landCmd = time.Now()
+	flightCmdMu.Unlock()
+}
+
+// takeOff returns an error, which it has already shown, if -checklist refuses it
+func takeOff() error {
+	if err := checkPreflight(); err != nil {
+		return err
+	}
+	noteTakeOff()
+	drone.TakeOff()
+	return nil
+}
+
+func land() {
+	noteLand()
+	drone.Land()
+}
+
+func palmLand() {
+	noteLand()
+	drone.PalmLand()
+}
+
+// flightState is called from updateFields with fieldsMu held
+func flightState(newFd tello.FlightData, now time.Time) (state string, fg termbox.Attribute) {
+	flightCmdMu.Lock()
+	sinceTakeOff, sinceLand := now.Sub(takeOffCmd), now.Sub(landCmd)
+	flightCmdMu.Unlock()
+	landing := sinceLand < sinceTakeOff && sinceLand < landStateTime
+	takingOff := sinceTakeOff < sinceLand && sinceTakeOff < takeOffStateTime
+
+	if newFd.Flying && !prevFd.Flying {
+		flyingSince = now
+	}
+	switch {
+	case !newFd.Flying && takingOff:
+		return "TAKING OFF", th.Caution | termbox.AttrBold
+	case !newFd.Flying:
+		return "GROUND", th.Value
+	case landing:
+		return "LANDING", th.Caution | termbox.AttrBold
+	case takingOff || now.Sub(flyingSince) < takeOffStateTime/2:
+		return "TAKING OFF", th.Caution | termbox.AttrBold
+	case newFd.DroneHover:
+		return "HOVER", th.Good | termbox.AttrBold
+	}
+	return "FLYING", th.Notice | termbox.AttrBold
+}
@@ -0,0 +1,161 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build grpc
+// +build grpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative tellopb/telloterm.proto
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"time"
+
+	"github.com/SMerrony/telloterm/tellopb"
+	"google.golang.org/grpc"
+)
+
+// grpcServer implements the TelloTerm service, its commands are queued with
+// those of -rpcsock clients by remoteCmdMu
+type grpcServer struct {
+	tellopb.UnimplementedTelloTermServer
+}
+
+func startGRPC(addr string) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatalf("Cannot listen for gRPC on %s - %v", addr, err)
+	}
+	s := grpc.NewServer()
+	tellopb.RegisterTelloTermServer(s, &grpcServer{})
+	go s.Serve(lis)
+}
+
+func (g *grpcServer) do(fn func() error) (*tellopb.Result, error) {
+	remoteCmdMu.Lock()
+	defer remoteCmdMu.Unlock()
+	if err := fn(); err != nil {
+		return &tellopb.Result{Ok: false, Error: err.Error()}, nil
+	}
+	return &tellopb.Result{Ok: true}, nil
+}
+
+func (g *grpcServer) TakeOff(context.Context, *tellopb.Empty) (*tellopb.Result, error) {
+	return g.do(takeOff)
+}
+
+func (g *grpcServer) ThrowTakeOff(context.Context, *tellopb.Empty) (*tellopb.Result, error) {
+	return g.do(throwTakeOff)
+}
+
+func (g *grpcServer) Land(context.Context, *tellopb.Empty) (*tellopb.Result, error) {
+	return g.do(func() error { land(); return nil })
+}
+
+func (g *grpcServer) PalmLand(context.Context, *tellopb.Empty) (*tellopb.Result, error) {
+	return g.do(func() error { palmLand(); return nil })
+}
+
+func (g *grpcServer) Hover(context.Context, *tellopb.Empty) (*tellopb.Result, error) {
+	return g.do(func() error { drone.Hover(); return nil })
+}
+
+func (g *grpcServer) Bounce(context.Context, *tellopb.Empty) (*tellopb.Result, error) {
+	return g.do(func() error { toggleBounce(); return nil })
+}
+
+func (g *grpcServer) TakePicture(context.Context, *tellopb.Empty) (*tellopb.Result, error) {
+	return g.do(func() error {
+		if !drone.TakePicture() {
+			return fmt.Errorf("camera busy")
+		}
+		return nil
+	})
+}
+
+func (g *grpcServer) Flip(_ context.Context, req *tellopb.FlipRequest) (*tellopb.Result, error) {
+	return g.do(func() error {
+		switch req.Direction {
+		case tellopb.FlipDirection_FLIP_FORWARD:
+			drone.ForwardFlip()
+		case tellopb.FlipDirection_FLIP_BACK:
+			drone.BackFlip()
+		case tellopb.FlipDirection_FLIP_LEFT:
+			drone.LeftFlip()
+		case tellopb.FlipDirection_FLIP_RIGHT:
+			drone.RightFlip()
+		default:
+			return fmt.Errorf("unknown flip direction %v", req.Direction)
+		}
+		return nil
+	})
+}
+
+func (g *grpcServer) SetHome(context.Context, *tellopb.Empty) (*tellopb.Result, error) {
+	return g.do(func() error { setHome(); return nil })
+}
+
+func (g *grpcServer) FlyTo(_ context.Context, req *tellopb.FlyToRequest) (*tellopb.Result, error) {
+	return g.do(func() error { return flyTo(req.X, req.Y) })
+}
+
+func (g *grpcServer) StreamFlightData(req *tellopb.StreamRequest, stream tellopb.TelloTerm_StreamFlightDataServer) error {
+	period := time.Duration(req.PeriodMs) * time.Millisecond
+	if period == 0 {
+		period = updatePeriodMs * time.Millisecond
+	}
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case now := <-ticker.C:
+			fd := currentFd()
+			err := stream.Send(&tellopb.FlightData{
+				TimeUnixMs:        now.UnixNano() / int64(time.Millisecond),
+				HeightM:           float32(fd.Height) / 10,
+				BatteryPercent:    int32(fd.BatteryPercentage),
+				BatteryMillivolts: int32(fd.BatteryMilliVolts),
+				WifiStrength:      int32(fd.WifiStrength),
+				Flying:            fd.Flying,
+				OnGround:          fd.OnGround,
+				Hovering:          fd.DroneHover,
+				NorthSpeed:        int32(fd.NorthSpeed),
+				EastSpeed:         int32(fd.EastSpeed),
+				VerticalSpeed:     int32(fd.VerticalSpeed),
+				PosX:              fd.MVO.PositionX,
+				PosY:              fd.MVO.PositionY,
+				PosZ:              fd.MVO.PositionZ,
+				Yaw:               int32(fd.IMU.Yaw),
+				Temperature:       int32(fd.IMU.Temperature),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,50 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"io"
+	"log"
+	"os"
+)
+
+// runHeadless executes the -script file (or stdin) without any terminal UI,
+// landing the drone afterwards if the script left it flying
+func runHeadless() {
+	var in io.Reader = os.Stdin
+	if *scriptFlag != "" {
+		f, err := os.Open(*scriptFlag)
+		if err != nil {
+			log.Fatalf("Cannot open script file - %v", err)
+		}
+		defer f.Close()
+		in = f
+	}
+	if err := runScript(in); err != nil {
+		logError("Script stopped - %v", err)
+	}
+	if currentFd().Flying {
+		logWarn("Tello still flying at end of script - landing")
+		landAndWait()
+	}
+}
@@ -0,0 +1,152 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/nsf/termbox-go"
+)
+
+const stateFileName = ".telloterm_state.json"
+
+// homePos is the home position in the drone's MVO frame
+type homePos struct {
+	X, Y, Z float32
+	Saved   time.Time
+}
+
+type savedState struct {
+	Home *homePos `json:",omitempty"`
+}
+
+var (
+	homeMu  sync.Mutex
+	home    *homePos // nil until home has been set or restored
+	homeOfX float32  // where home is relative to the library's home point,
+	homeOfY float32  // non-zero only when an old home has been restored
+)
+
+func stateFilePath() string {
+	dir, err := os.UserHomeDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, stateFileName)
+}
+
+// setHome makes the current position home and remembers it for the next session
+func setHome() {
+	fd := currentFd()
+	drone.SetHome()
+	homeMu.Lock()
+	home = &homePos{X: fd.MVO.PositionX, Y: fd.MVO.PositionY, Z: fd.MVO.PositionZ}
+	homeOfX, homeOfY = 0, 0
+	homeMu.Unlock()
+}
+
+// homeLabel gives the text for the Home Pos field, set is whether the drone has a home
+func homeLabel(set bool, h *homePos) string {
+	switch {
+	case !set:
+		return "Unset"
+	case h == nil:
+		return "Set"
+	default:
+		return fmt.Sprintf("Set (%.1f, %.1f)", h.X, h.Y)
+	}
+}
+
+// goHome flies back to the home position
+func goHome() error {
+	homeMu.Lock()
+	x, y := homeOfX, homeOfY
+	homeMu.Unlock()
+	return flyTo(x, y)
+}
+
+// saveHome records the home position in the state file, it does nothing if home was never set
+func saveHome() error {
+	homeMu.Lock()
+	defer homeMu.Unlock()
+	if home == nil {
+		return nil
+	}
+	home.Saved = time.Now()
+	buf, err := json.MarshalIndent(savedState{Home: home}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(stateFilePath(), buf, 0644)
+}
+
+func loadSavedHome() *homePos {
+	buf, err := ioutil.ReadFile(stateFilePath())
+	if err != nil {
+		return nil
+	}
+	var st savedState
+	if json.Unmarshal(buf, &st) != nil {
+		return nil
+	}
+	return st.Home
+}
+
+// offerSavedHome asks the user whether to reuse a home position from an earlier run.
+// The MVO frame is only meaningful while the drone stays powered in the same place,
+// hence the confirmation.  As the library can only set home at the current position,
+// that is done and the saved home is kept as an offset from it.
+func offerSavedHome() {
+	saved := loadSavedHome()
+	if saved == nil {
+		return
+	}
+	for start := time.Now(); time.Since(start) < linkTimeout; {
+		fieldsMu.RLock()
+		haveData := !lastFdTime.IsZero()
+		fieldsMu.RUnlock()
+		if haveData {
+			break
+		}
+		time.Sleep(updatePeriodMs * time.Millisecond)
+	}
+	showMessage(fmt.Sprintf("Reuse home (%.1f, %.1f) saved %s?  y/n",
+		saved.X, saved.Y, saved.Saved.Format("Jan 2 15:04")))
+	ev := termbox.PollEvent()
+	showMessage("")
+	if ev.Type != termbox.EventKey || ev.Ch != 'y' {
+		return
+	}
+	fd := currentFd()
+	drone.SetHome()
+	homeMu.Lock()
+	home = saved
+	homeOfX, homeOfY = saved.X-fd.MVO.PositionX, saved.Y-fd.MVO.PositionY
+	homeMu.Unlock()
+}
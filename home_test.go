@@ -0,0 +1,44 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import "testing"
+
+func TestHomeLabel(t *testing.T) {
+	tests := []struct {
+		set  bool
+		h    *homePos
+		want string
+	}{
+		{false, nil, "Unset"},
+		{false, &homePos{X: 1, Y: 2}, "Unset"},
+		{true, nil, "Set"},
+		{true, &homePos{X: 1.25, Y: -3.04, Z: 0.5}, "Set (1.2, -3.0)"},
+		{true, &homePos{}, "Set (0.0, 0.0)"},
+	}
+	for _, tt := range tests {
+		if got := homeLabel(tt.set, tt.h); got != tt.want {
+			t.Errorf("homeLabel(%v, %+v) = %q, want %q", tt.set, tt.h, got, tt.want)
+		}
+	}
+}
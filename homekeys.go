@@ -0,0 +1,88 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"log"
+)
+
+// The <Home> key normally sets home when there is none and flies home when
+// there is, -homekey can limit it to one of these, and -sethomekey and
+// -gohomekey give each action a key of its own.
+
+var setHomeKey, goHomeKey rune // 0 unless given
+
+func setupHomeKeys() {
+	switch *homeKeyFlag {
+	case "both", "set", "go", "none":
+	default:
+		log.Fatalf("Unknown -homekey action <%s>, choose from both, set, go or none", *homeKeyFlag)
+	}
+	setHomeKey = keyOption("sethomekey", *setHomeKeyFlag)
+	goHomeKey = keyOption("gohomekey", *goHomeKeyFlag)
+	if setHomeKey != 0 && setHomeKey == goHomeKey {
+		log.Fatalln("The -sethomekey and -gohomekey options must be different keys")
+	}
+}
+
+// homeKeyAction describes what the <Home> key will do now
+func homeKeyAction(homeSet bool) string {
+	switch *homeKeyFlag {
+	case "set":
+		return "Set home"
+	case "go":
+		if !homeSet {
+			return "Nothing"
+		}
+		return "Fly home"
+	case "none":
+		return "Nothing"
+	}
+	if homeSet {
+		return "Fly home"
+	}
+	return "Set home"
+}
+
+// homeKey carries out the <Home> key's action
+func homeKey() {
+	switch homeKeyAction(drone.IsHomeSet()) {
+	case "Set home":
+		setHome()
+	case "Fly home":
+		goHome()
+	default:
+		return
+	}
+	flashBanner()
+}
+
+// flyHomeKey is the -gohomekey action, which says why when it cannot fly home
+func flyHomeKey() {
+	if err := goHome(); err != nil {
+		showMessage(fmt.Sprintf("Cannot fly home - %v", err))
+		return
+	}
+	flashBanner()
+}
@@ -0,0 +1,82 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// the Tello is always 192.168.10.1 on its own WiFi network
+var telloNet = net.IPNet{IP: net.IPv4(192, 168, 10, 0), Mask: net.CIDRMask(24, 32)}
+
+func listInterfaces() {
+	ifs, err := net.Interfaces()
+	if err != nil {
+		fmt.Printf("Cannot list interfaces - %v\n", err)
+		return
+	}
+	for _, ifc := range ifs {
+		addrs, _ := ifc.Addrs()
+		var list []string
+		for _, a := range addrs {
+			list = append(list, a.String())
+		}
+		note := ""
+		if onTelloNet(addrs) {
+			note = "  <- Tello network"
+		}
+		fmt.Printf("%-16s %s%s\n", ifc.Name, strings.Join(list, ", "), note)
+	}
+}
+
+func onTelloNet(addrs []net.Addr) bool {
+	for _, a := range addrs {
+		if ipn, ok := a.(*net.IPNet); ok && telloNet.Contains(ipn.IP) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkInterface confirms that the named interface is up and on the Tello's network.
+// The tello library gives no way to bind its sockets to an address, so routing
+// decides which interface is used, and that goes wrong when this check fails.
+func checkInterface(name string) error {
+	ifc, err := net.InterfaceByName(name)
+	if err != nil {
+		return err
+	}
+	if ifc.Flags&net.FlagUp == 0 {
+		return fmt.Errorf("interface %s is down", name)
+	}
+	addrs, err := ifc.Addrs()
+	if err != nil {
+		return err
+	}
+	if !onTelloNet(addrs) {
+		return fmt.Errorf("interface %s has no address on %s, is it joined to the Tello's WiFi?", name, telloNet.String())
+	}
+	return nil
+}
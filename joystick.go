@@ -25,7 +25,11 @@ package main
 import (
 	"fmt"
 	"log"
+	"math"
 	"runtime"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/SMerrony/tello"
@@ -33,7 +37,7 @@ import (
 )
 
 var (
-	js       joystick.Joystick
+	jss      []joystick.Joystick // one per -jsid, in the order given
 	jsConfig joystickConfig
 	err      error
 )
@@ -63,20 +67,50 @@ const (
 	btnUnknown
 )
 
+// btnUnmapped marks a logical button that no physical button drives
+const btnUnmapped = ^uint(0)
+
 const deadZone = 2000
 
+const jsCalTime = time.Second
+
+// per-axis centre offsets subtracted from the raw readings, indexed by axLeftX etc.
+var jsOffsets [4]int
+
 type joystickConfig struct {
 	axes    []int
 	buttons []uint
+	// which of the -jsid devices each axis and button is on, indexed
+	// like axes and buttons, anything not covered is on the first device
+	axisDevs   []int
+	buttonDevs []int
+	macros     map[int][]string // command lines run by a button instead of its usual action
+}
+
+func (c joystickConfig) axisDev(ax int) int {
+	if ax < len(c.axisDevs) {
+		return c.axisDevs[ax]
+	}
+	return 0
+}
+
+func (c joystickConfig) buttonDev(btn int) int {
+	if btn < len(c.buttonDevs) {
+		return c.buttonDevs[btn]
+	}
+	return 0
 }
 
+// axes that a mapping does not have are -1
+
 var dualShock4Config = joystickConfig{
 	axes: []int{
 		axLeftX: 0, axLeftY: 1, axRightX: 3, axRightY: 4,
+		axL1: -1, axL2: 2, axR1: -1, axR2: 5,
 	},
 	buttons: []uint{
 		btnX: 0, btnCircle: 1, btnTriangle: 2, btnSquare: 3, btnL1: 4,
-		btnL2: 6, btnR1: 5, btnR2: 7,
+		btnL2: 6, btnR1: 5, btnR2: 7, btnL3: 11, btnR3: 12,
 	},
 }
 
@@ -86,7 +120,7 @@ var dualShock4ConfigWin = joystickConfig{
 	},
 	buttons: []uint{
 		btnX: 1, btnCircle: 2, btnTriangle: 3, btnSquare: 0, btnL1: 4,
-		btnL2: 6, btnR1: 5, btnR2: 7,
+		btnL2: 6, btnR1: 5, btnR2: 7, btnL3: 10, btnR3: 11,
 	},
 }
 
@@ -101,44 +135,167 @@ var tflightHotasXConfig = joystickConfig{
 	},
 }
 
+// Nintendo Switch Pro controller, buttons are mapped by position so that
+// B/A/X/Y take the places of X/Circle/Triangle/Square on the DualShock4.
+// Raw Linux (hid-nintendo) indices -
+//
+//	axes:    0 LX, 1 LY, 2 RX, 3 RY
+//	buttons: 0 B, 1 A, 2 X, 3 Y, 4 Capture, 5 L, 6 R, 7 ZL, 8 ZR,
+//	         9 Minus, 10 Plus, 11 Home, 12 LStick, 13 RStick
+var proControllerConfig = joystickConfig{
+	axes: []int{
+		axLeftX: 0, axLeftY: 1, axRightX: 2, axRightY: 3,
+	},
+	buttons: []uint{
+		btnX: 0, btnCircle: 1, btnTriangle: 2, btnSquare: 3, btnL1: 5,
+		btnL2: 7, btnR1: 6, btnR2: 8, btnL3: 12, btnR3: 13,
+	},
+}
+
+// Raw Windows (DirectInput) indices -
+//
+//	axes:    0 LX, 1 LY, 2 RX, 3 RY
+//	buttons: 0 B, 1 A, 2 Y, 3 X, 4 L, 5 R, 6 ZL, 7 ZR,
+//	         8 Minus, 9 Plus, 10 LStick, 11 RStick, 12 Home, 13 Capture
+var proControllerConfigWin = joystickConfig{
+	axes: []int{
+		axLeftX: 0, axLeftY: 1, axRightX: 2, axRightY: 3,
+	},
+	buttons: []uint{
+		btnX: 0, btnCircle: 1, btnTriangle: 3, btnSquare: 2, btnL1: 4,
+		btnL2: 6, btnR1: 5, btnR2: 7, btnL3: 10, btnR3: 11,
+	},
+}
+
+// best-effort layout shared by many unbranded two-stick gamepads
+var genericConfig = joystickConfig{
+	axes: []int{
+		axLeftX: 0, axLeftY: 1, axRightX: 2, axRightY: 3,
+	},
+	buttons: []uint{
+		btnX: 0, btnCircle: 1, btnTriangle: 3, btnSquare: 2, btnL1: 4,
+		btnL2: 6, btnR1: 5, btnR2: 7, btnL3: 10, btnR3: 11,
+	},
+}
+
+// SDL2 game controllers all share one mapping whatever the device or OS,
+// the triggers are axes in SDL so L2/R2 are made into buttons by jssdl.go
+var sdlControllerConfig = joystickConfig{
+	axes: []int{
+		axLeftX: 0, axLeftY: 1, axRightX: 2, axRightY: 3,
+		axL1: -1, axL2: 4, axR1: -1, axR2: 5,
+	},
+	buttons: []uint{
+		btnX: 0, btnCircle: 1, btnTriangle: 3, btnSquare: 2, btnL1: 9,
+		btnL2: sdlButtonL2, btnR1: 10, btnR2: sdlButtonR2, btnL3: 7, btnR3: 8,
+	},
+}
+
+// buttons synthesised from the SDL trigger axes
+const (
+	sdlButtonL2 = 30
+	sdlButtonR2 = 31
+)
+
+// openJoystick opens a device with the -jsbackend chosen
+func openJoystick(id int) (joystick.Joystick, error) {
+	switch *jsBackendFlag {
+	case "native":
+		return joystick.Open(id)
+	case "sdl":
+		return openSDLJoystick(id)
+	}
+	return nil, fmt.Errorf("unknown joystick backend <%s>", *jsBackendFlag)
+}
+
 func printJoystickHelp() {
 	fmt.Print(
 		`TelloTerm Joystick Control Mapping
 
 Right Stick  Forward/Backward/Left/Right
 Left Stick   Up/Down/Turn
+             (in the default -stickmode 2, mode 1 swaps Forward/Backward
+             with Up/Down, and modes 3 and 4 are 2 and 1 with the sticks swapped)
 Triangle     Takeoff
 X            Land
-Circle       
+Circle       Start/Stop Video
 Square       Take Photo
 L1           Bounce (on/off)
 L2           Palm Land
+L3           Slow (normal) flight mode
+R3           Fast (sports) flight mode
+R1+Triangle  360 degree smart video flight
+R1+Circle    Circle smart video flight
+R1+Square    Up and out smart video flight
+R1+X         Return home and land
+R1+L3/R3     Trim yaw left/right, to stop a slow turn with the stick centred
+             (the starting trim can be set with "yawtrim" in telloterm.json)
+L2/R2 axes   Descend/Climb in proportion, with -triggers add or override
+
+Supported -jstype values: DualShock4, HotasX, SwitchPro, Generic
+Any mapping may be adjusted with a -jsconfig JSON file.
+With -jsbackend sdl no -jstype is needed, SDL2 maps every known game
+controller to the same layout on all systems.
+On the Switch Pro controller B/A/X/Y act as X/Circle/Triangle/Square,
+L/ZL/R/ZR as L1/L2/R1/R2 and the stick clicks as L3/R3.
 `)
 }
 
+// listJoysticks shows every joystick with an ID below -jsmax, there may be
+// gaps in the IDs, e.g. after a controller has been unplugged
 func listJoysticks() {
-	for jsid := 0; jsid < 10; jsid++ {
-		js, err := joystick.Open(jsid)
+	if *jsMaxFlag < 1 {
+		log.Fatalln("The -jsmax number of IDs must be at least 1")
+	}
+	found := 0
+	for jsid := 0; jsid < *jsMaxFlag; jsid++ {
+		js, err := openJoystick(jsid)
 		if err != nil {
-			if jsid == 0 {
-				fmt.Println("No joysticks detected")
-			}
-			return
+			continue
 		}
 		fmt.Printf("Joystick ID: %d: Name: %s, Axes: %d, Buttons: %d\n", jsid, js.Name(), js.AxisCount(), js.ButtonCount())
 		js.Close()
+		found++
+	}
+	if found == 0 {
+		fmt.Printf("No joysticks detected with IDs 0 to %d\n", *jsMaxFlag-1)
 	}
 }
 
-func setupJoystick(id int) bool {
-	if jsTypeFlag == nil || *jsTypeFlag == "" {
-		log.Fatalln("No joystick type supplied, please use -jstype option")
+// openJoysticks opens each of the comma-separated IDs into jss
+func openJoysticks(ids string) {
+	for _, s := range strings.Split(ids, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil {
+			log.Fatalf("Bad joystick ID <%s> in -jsid\n", s)
+		}
+		js, err := openJoystick(id)
+		if err != nil {
+			log.Fatalf("Could not open specified joystick ID:%d - %v\n", id, err)
+		}
+		jss = append(jss, js)
 	}
-	js, err = joystick.Open(id)
-	if err != nil {
-		log.Fatalf("Could not open specified joystick ID:%d\n", id)
+}
+
+// setupJoystick opens the joysticks in ids, a comma-separated list of IDs,
+// several devices (e.g. a separate HOTAS throttle and stick) act as one
+// with the -jsconfig file saying which device each axis and button is on
+func setupJoystick(ids string) bool {
+	sdlBackend := *jsBackendFlag == "sdl"
+	if !sdlBackend && *jsTypeFlag == "" && *jsConfigFlag == "" {
+		log.Fatalln("No joystick type supplied, please use -jstype or -jsconfig option")
 	}
-	switch *jsTypeFlag {
+	openJoysticks(ids)
+	jsType := *jsTypeFlag
+	if sdlBackend {
+		if jsType != "" {
+			logWarn("-jstype is ignored with -jsbackend sdl")
+		}
+		jsType = "sdl"
+	}
+	switch jsType {
+	case "sdl":
+		jsConfig = sdlControllerConfig
 	case "DualShock4":
 		switch runtime.GOOS {
 		case "windows":
@@ -148,56 +305,484 @@ func setupJoystick(id int) bool {
 		}
 	case "HotasX":
 		jsConfig = tflightHotasXConfig
+	case "SwitchPro":
+		switch runtime.GOOS {
+		case "windows":
+			jsConfig = proControllerConfigWin
+		default:
+			jsConfig = proControllerConfig
+		}
+	case "Generic":
+		jsConfig = genericConfig
+		logWarn("The Generic joystick mapping is a guess, check it with -jstest and adjust it via -jsconfig")
+	case "":
+		// everything must come from -jsconfig
 	default:
 		log.Fatalf("Unknown joystick type <%s> supplied\n", *jsTypeFlag)
 	}
+	if *jsConfigFlag != "" {
+		jsConfig, err = loadJoystickConfig(*jsConfigFlag, jsConfig)
+		if err != nil {
+			log.Fatalf("Could not load joystick config %s - %v\n", *jsConfigFlag, err)
+		}
+	}
+	for ax := range jsConfig.axisDevs {
+		if jsConfig.axisDevs[ax] >= len(jss) {
+			log.Fatalf("Joystick config uses device %d but -jsid only gives %d\n", jsConfig.axisDevs[ax], len(jss))
+		}
+	}
+	for btn := range jsConfig.buttonDevs {
+		if jsConfig.buttonDevs[btn] >= len(jss) {
+			log.Fatalf("Joystick config uses device %d but -jsid only gives %d\n", jsConfig.buttonDevs[btn], len(jss))
+		}
+	}
+	checkJoystickCounts()
+	if *jsSmoothFlag < 0 || *jsSmoothFlag >= 1 {
+		log.Fatalln("The -jssmooth factor must be at least 0 and less than 1")
+	}
+	if *throttleBandFlag < 0 || *throttleBandFlag > 50 {
+		log.Fatalln("The -throttleband percentage must be between 0 and 50")
+	}
+	if _, ok := stickModes[*stickModeFlag]; !ok {
+		log.Fatalln("The -stickmode must be 1, 2, 3 or 4")
+	}
+	switch *triggersFlag {
+	case "off", "add", "override":
+	default:
+		log.Fatalf("Unknown -triggers mode <%s>, choose from off, add or override\n", *triggersFlag)
+	}
+	if *triggersFlag != "off" && (!axisMapped(axL2) || !axisMapped(axR2)) {
+		logWarn("-triggers needs L2 and R2 axes in the joystick mapping, add them with -jsconfig")
+	}
+	if *maxStickFlag < 1 || *maxStickFlag > 100 {
+		log.Fatalln("The -maxstick percentage must be between 1 and 100")
+	}
+	if *slewRateFlag < 0 || *slewRateFlag > 100 {
+		log.Fatalln("The -slewrate percentage must be between 0 and 100")
+	}
+	if cfg.get("yawtrim", &yawTrim) && (yawTrim < -yawTrimMax || yawTrim > yawTrimMax) {
+		log.Fatalf("The yawtrim config entry must be between %d and %d\n", -yawTrimMax, yawTrimMax)
+	}
+	var offsets []int
+	if cfg.get("jsoffsets", &offsets) {
+		if len(offsets) != len(jsOffsets) {
+			log.Fatalf("The jsoffsets config entry must have %d values\n", len(jsOffsets))
+		}
+		copy(jsOffsets[:], offsets)
+	}
+	if *jsCheckFlag {
+		checkJoystickCentre()
+	}
+	if *jsCalFlag {
+		calibrateJoystick()
+	}
 	return true
 }
 
-func intAbs(x int16) int16 {
-	if x < 0 {
-		return -x
+// checkJoystickCounts makes sure that every stick axis the mapping uses exists
+// on its device, a wrong -jstype would otherwise crash readJoystick.  Missing
+// buttons are only warned about as many pads lack a few (e.g. L3/R3).
+func checkJoystickCounts() {
+	for ax := axLeftX; ax <= axRightY; ax++ {
+		dev := jsConfig.axisDev(ax)
+		if ax >= len(jsConfig.axes) {
+			log.Fatalf("The joystick mapping has no %s axis\n", mappingName(axisNames, ax))
+		}
+		if n := jss[dev].AxisCount(); jsConfig.axes[ax] >= n {
+			log.Fatalf("The joystick mapping uses axis %d for %s but %s only has %d axes - check -jstype\n",
+				jsConfig.axes[ax], mappingName(axisNames, ax), jss[dev].Name(), n)
+		}
+	}
+	for btn := 0; btn < len(jsConfig.buttons) && btn < btnUnknown; btn++ {
+		dev := jsConfig.buttonDev(btn)
+		if jsConfig.buttons[btn] == btnUnmapped {
+			continue
+		}
+		if n := jss[dev].ButtonCount(); int(jsConfig.buttons[btn]) >= n {
+			logWarn("The joystick mapping uses button %d for %s but %s only has %d buttons - check -jstype",
+				jsConfig.buttons[btn], mappingName(buttonNames, btn), jss[dev].Name(), n)
+		}
 	}
-	return x
 }
 
-func readJoystick(test bool) {
+// a centred stick reading further from zero than this suggests the wrong axis,
+// often a trigger which rests at full scale
+const jsCentreLimit = 8000
+
+var jsWarning string // shown once the display is up
+
+// checkJoystickCentre reads the sticks for a moment and warns if any mapped stick
+// axis is far from zero, which usually means the wrong Windows/Linux variant
+func checkJoystickCentre() {
+	var sums [4]int
+	n := 0
+	fmt.Println("Checking the joystick - leave the sticks centred...")
+	for start := time.Now(); time.Since(start) < jsCalTime; n++ {
+		jsStates, err := readJoysticks()
+		if err != nil {
+			logError("Cannot check the joystick: %v", err)
+			return
+		}
+		for ax := range sums {
+			sums[ax] += axisValue(jsStates, ax)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	var off []string
+	for ax := range sums {
+		if avg := sums[ax] / n; absInt(avg) > jsCentreLimit {
+			off = append(off, fmt.Sprintf("%s (axis %d) reads %d", mappingName(axisNames, ax), jsConfig.axes[ax], avg))
+		}
+	}
+	if len(off) == 0 {
+		return
+	}
+	jsWarning = fmt.Sprintf("Joystick not centred: %s - wrong -jstype for %s?", strings.Join(off, ", "), runtime.GOOS)
+	logWarn("%s", jsWarning)
+	logWarn("Check the mapping with -jstest, or skip this check with -jscheck=false")
+}
+
+// mappingName finds the config file name for an axis or button
+func mappingName(names map[string]int, ix int) string {
+	for name, i := range names {
+		if i == ix {
+			return name
+		}
+	}
+	return fmt.Sprint(ix)
+}
+
+// calibrateJoystick averages the stick readings at rest to find their centre offsets
+func calibrateJoystick() {
 	var (
-		sm                 tello.StickMessage
-		jsState, prevState joystick.State
-		err                error
+		sums [4]int
+		n    int
 	)
+	fmt.Println("Calibrating joystick - leave the sticks centred...")
+	for start := time.Now(); time.Since(start) < jsCalTime; n++ {
+		jsStates, err := readJoysticks()
+		if err != nil {
+			log.Fatalf("Error reading joystick during calibration: %v\n", err)
+		}
+		for ax := range sums {
+			sums[ax] += jsStates[jsConfig.axisDev(ax)].AxisData[jsConfig.axes[ax]]
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	for ax := range jsOffsets {
+		jsOffsets[ax] = sums[ax] / n
+	}
+	fmt.Printf("Joystick centre offsets: %v\n", jsOffsets)
+	fmt.Printf("To keep them, add this to your %s: \"jsoffsets\": [%d, %d, %d, %d]\n",
+		configFileName, jsOffsets[0], jsOffsets[1], jsOffsets[2], jsOffsets[3])
+}
 
-	for {
-		jsState, err = js.Read()
+// axisValue returns the centre-corrected raw reading for the given logical axis
+func axisValue(sts []joystick.State, ax int) int {
+	data := sts[jsConfig.axisDev(ax)].AxisData
+	if jsConfig.axes[ax] < 0 || jsConfig.axes[ax] >= len(data) { // checked at startup, but don't crash mid-flight
+		return 0
+	}
+	return data[jsConfig.axes[ax]] - jsOffsets[ax]
+}
 
-		if err != nil {
-			log.Printf("Error reading joystick: %v\n", err)
+// stickMode says which physical stick axis drives each control
+type stickMode struct {
+	yaw, throttle, roll, pitch int
+}
+
+// the standard RC transmitter modes, indexed by -stickmode
+var stickModes = map[int]stickMode{
+	1: {yaw: axLeftX, pitch: axLeftY, roll: axRightX, throttle: axRightY},
+	2: {yaw: axLeftX, throttle: axLeftY, roll: axRightX, pitch: axRightY},
+	3: {roll: axLeftX, pitch: axLeftY, yaw: axRightX, throttle: axRightY},
+	4: {roll: axLeftX, throttle: axLeftY, yaw: axRightX, pitch: axRightY},
+}
+
+// axisMapped reports whether the mapping has the given logical axis
+func axisMapped(ax int) bool {
+	return ax < len(jsConfig.axes) && jsConfig.axes[ax] >= 0
+}
+
+// trigRest holds each trigger's first reading, taken to be released, as some
+// drivers rest triggers at -32767 and others at 0
+var (
+	trigRest    [2]int
+	trigRestSet bool
+)
+
+// triggerLift gives a vertical stick value from the L2 (descend) and R2 (climb)
+// trigger axes, with the dead zone applied
+func triggerLift(sts []joystick.State) int16 {
+	if !axisMapped(axL2) || !axisMapped(axR2) {
+		return 0
+	}
+	if !trigRestSet {
+		trigRest = [2]int{axisValue(sts, axL2), axisValue(sts, axR2)}
+		trigRestSet = true
+	}
+	pull := func(ax, rest int) int {
+		if rest >= 32767 {
+			return 0
 		}
+		v := (axisValue(sts, ax) - rest) * 32767 / (32767 - rest)
+		if v < int(deadZone) {
+			return 0
+		}
+		return v
+	}
+	return clampStick(pull(axR2, trigRest[1]) - pull(axL2, trigRest[0]))
+}
 
-		if jsState.AxisData[jsConfig.axes[axLeftX]] == 32768 {
-			sm.Lx = 32767
-		} else {
-			sm.Lx = int16(jsState.AxisData[jsConfig.axes[axLeftX]])
+// applyTriggers combines the trigger lift with the left stick's vertical value per -triggers
+func applyTriggers(ly int16, sts []joystick.State) int16 {
+	switch *triggersFlag {
+	case "add":
+		return clampStick(int(ly) + int(triggerLift(sts)))
+	case "override":
+		if lift := triggerLift(sts); lift != 0 {
+			return lift
 		}
+	}
+	return ly
+}
 
-		if jsState.AxisData[jsConfig.axes[axLeftY]] == 32768 {
-			sm.Ly = -32767
-		} else {
-			sm.Ly = -int16(jsState.AxisData[jsConfig.axes[axLeftY]])
+// buttonDown reports whether the given logical button is held, sts may be empty
+func buttonDown(sts []joystick.State, btn int) bool {
+	dev := jsConfig.buttonDev(btn)
+	if btn >= len(jsConfig.buttons) || jsConfig.buttons[btn] == btnUnmapped || dev >= len(sts) {
+		return false
+	}
+	return sts[dev].Buttons&(1<<jsConfig.buttons[btn]) != 0
+}
+
+// pressed reports whether the button has gone down since the previous reading,
+// it is always false for buttons with macros
+func pressed(sts, prev []joystick.State, btn int) bool {
+	if _, ok := jsConfig.macros[btn]; ok {
+		return false
+	}
+	return buttonDown(sts, btn) && !buttonDown(prev, btn)
+}
+
+// readJoysticks reads every open device, returning the first error seen
+func readJoysticks() ([]joystick.State, error) {
+	var firstErr error
+	sts := make([]joystick.State, len(jss))
+	for i, js := range jss {
+		st, err := js.Read()
+		if err != nil && firstErr == nil {
+			firstErr = err
 		}
+		sts[i] = st
+	}
+	return sts, firstErr
+}
 
-		if jsState.AxisData[jsConfig.axes[axRightX]] == 32768 {
-			sm.Rx = 32767
-		} else {
-			sm.Rx = int16(jsState.AxisData[jsConfig.axes[axRightX]])
+// clampStick limits a reading to the range a StickMessage accepts,
+// some drivers report 32768 at full deflection
+func clampStick(v int) int16 {
+	if v > 32767 {
+		return 32767
+	}
+	if v < -32767 {
+		return -32767
+	}
+	return int16(v)
+}
+
+func intAbs(x int16) int16 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func absInt(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// smoothAxis applies an exponential moving average to a stick value, dt is the
+// time since the last reading and avg holds the running average for the axis
+// between calls.  -jssmooth is the weight kept per nominal update period, it is
+// turned into a time constant so that irregular readings smooth the same.
+func smoothAxis(avg *float64, v int16, dt time.Duration) int16 {
+	if *jsSmoothFlag == 0 {
+		return v
+	}
+	tau := -float64(updatePeriodMs*time.Millisecond) / math.Log(*jsSmoothFlag)
+	alpha := 1 - math.Exp(-float64(dt)/tau)
+	*avg += alpha * (float64(v) - *avg)
+	return int16(math.Round(*avg))
+}
+
+// throttleHold gives zero vertical rate while the throttle is within the
+// -throttleband, outside it the remaining travel is rescaled to start from zero
+func throttleHold(v int16) int16 {
+	band := *throttleBandFlag * 32767 / 100
+	if band == 0 {
+		return v
+	}
+	mag := int(intAbs(v))
+	if mag <= band {
+		return 0
+	}
+	out := (mag - band) * 32767 / (32767 - band)
+	if v < 0 {
+		return int16(-out)
+	}
+	return int16(out)
+}
+
+// limitStick scales a stick value so that full deflection gives -maxstick percent
+func limitStick(v int16) int16 {
+	return int16(int(v) * *maxStickFlag / 100)
+}
+
+// slewStick limits how far a stick value can move away from centre since the
+// previous value sent, dt after it.  Easing off towards centre is never slowed,
+// so letting go of the stick still stops the drone at once.
+func slewStick(prev, v int16, dt time.Duration) int16 {
+	if *slewRateFlag == 0 {
+		return v
+	}
+	if (prev < 0) != (v < 0) { // reversing, starts again from centre
+		prev = 0
+	}
+	if intAbs(v) <= intAbs(prev) {
+		return v
+	}
+	step := int(float64(*slewRateFlag*32767/100) * float64(dt) / float64(updatePeriodMs*time.Millisecond))
+	if step < 1 {
+		step = 1
+	}
+	if absInt(int(v)-int(prev)) <= step {
+		return v
+	}
+	if v > prev {
+		return int16(int(prev) + step)
+	}
+	return int16(int(prev) - step)
+}
+
+const (
+	yawTrimStep = 1  // percent of full turn rate per press
+	yawTrimMax  = 20 // percent
+)
+
+var yawTrim int // percent of full turn rate added to the yaw stick, only used by readJoystick
+
+// adjustYawTrim changes the yaw trim by steps and shows the result
+func adjustYawTrim(steps int) {
+	yawTrim = clampInt(yawTrim+steps*yawTrimStep, -yawTrimMax, yawTrimMax)
+	showMessage(fmt.Sprintf("Yaw trim %+d%%", yawTrim))
+}
+
+// trimYaw applies the yaw trim to a stick value
+func trimYaw(v int16) int16 {
+	return clampStick(int(v) + yawTrim*32767/100)
+}
+
+// testAxis shows a raw axis reading alongside the value that would be sent to the drone,
+// noting when the dead zone has swallowed it
+func testAxis(sts []joystick.State, ax int, out int16) string {
+	raw := axisValue(sts, ax)
+	s := fmt.Sprintf("%d -> %d", raw, out)
+	if raw != 0 && intAbs(clampStick(raw)) < deadZone {
+		s += " (dead)"
+	}
+	return s
+}
+
+var smartVideoButtons = []struct {
+	btn  int
+	name string
+	cmd  tello.SvCmd
+}{
+	{btnTriangle, "Triangle", tello.Sv360},
+	{btnCircle, "Circle", tello.SvCircle},
+	{btnSquare, "Square", tello.SvUpOut},
+}
+
+const jsStallTimeout = 500 * time.Millisecond
+
+var (
+	jsReadMu   sync.Mutex
+	lastJsRead time.Time // when the joystick was last read successfully
+	jsStalled  bool      // the sticks have been centred because reads stopped
+)
+
+// noteJsRead records a good joystick read, clearing any stall warning
+func noteJsRead() {
+	jsReadMu.Lock()
+	lastJsRead = time.Now()
+	recovered := jsStalled
+	jsStalled = false
+	jsReadMu.Unlock()
+	if recovered {
+		showMessage("Joystick OK")
+	}
+}
+
+// watchJsStall keeps sending centred sticks while the joystick is not being read,
+// so that a blocked or failing controller leaves the drone hovering rather than
+// repeating its last command
+func watchJsStall() {
+	for range time.Tick(updatePeriodMs * time.Millisecond) {
+		jsReadMu.Lock()
+		stale := time.Since(lastJsRead) > jsStallTimeout
+		warn := stale && !jsStalled
+		if stale {
+			jsStalled = true
+		}
+		jsReadMu.Unlock()
+		if !stale {
+			continue
 		}
+		stickChan <- tello.StickMessage{}
+		if warn {
+			logWarn("Joystick reads stalled - sticks centred")
+			showMessage("Joystick not responding - sticks centred")
+		}
+	}
+}
 
-		if jsState.AxisData[jsConfig.axes[axRightY]] == 32768 {
-			sm.Ry = -32767
-		} else {
-			sm.Ry = -int16(jsState.AxisData[jsConfig.axes[axRightY]])
+func readJoystick(test bool) {
+	var (
+		sm, prevSm           tello.StickMessage
+		jsStates, prevStates []joystick.State
+		err                  error
+		avgLx, avgLy         float64
+		avgRx, avgRy         float64
+		mode                 = stickModes[*stickModeFlag]
+		lastRead             = time.Now()
+	)
+
+	if !test {
+		noteJsRead()
+		go watchJsStall()
+	}
+
+	for {
+		jsStates, err = readJoysticks()
+
+		if err != nil {
+			// leave the sticks alone, the stall watcher will centre them if this persists
+			logError("Error reading joystick: %v", err)
+			time.Sleep(updatePeriodMs * time.Millisecond)
+			continue
 		}
+		noteJsRead()
+
+		// the StickMessage is always laid out as Mode 2, Y axes are inverted
+		// so that pushing the stick forward is positive
+		sm.Lx = clampStick(axisValue(jsStates, mode.yaw))
+		sm.Ly = -clampStick(axisValue(jsStates, mode.throttle))
+		sm.Rx = clampStick(axisValue(jsStates, mode.roll))
+		sm.Ry = -clampStick(axisValue(jsStates, mode.pitch))
 
 		if intAbs(sm.Lx) < deadZone {
 			sm.Lx = 0
@@ -212,59 +797,158 @@ func readJoystick(test bool) {
 			sm.Ry = 0
 		}
 
+		sm.Ly = throttleHold(sm.Ly)
+		sm.Ly = applyTriggers(sm.Ly, jsStates)
+
+		now := time.Now()
+		dt := now.Sub(lastRead)
+		lastRead = now
+
+		sm.Lx = smoothAxis(&avgLx, sm.Lx, dt)
+		sm.Ly = smoothAxis(&avgLy, sm.Ly, dt)
+		sm.Rx = smoothAxis(&avgRx, sm.Rx, dt)
+		sm.Ry = smoothAxis(&avgRy, sm.Ry, dt)
+
+		sm.Lx = limitStick(sm.Lx)
+		sm.Ly = limitStick(sm.Ly)
+		sm.Rx = limitStick(sm.Rx)
+		sm.Ry = limitStick(sm.Ry)
+
+		sm.Lx = slewStick(prevSm.Lx, sm.Lx, dt)
+		sm.Ly = slewStick(prevSm.Ly, sm.Ly, dt)
+		sm.Rx = slewStick(prevSm.Rx, sm.Rx, dt)
+		sm.Ry = slewStick(prevSm.Ry, sm.Ry, dt)
+
 		if test {
-			log.Printf("JS: Lx: %d, Ly: %d, Rx: %d, Ry: %d\n", sm.Lx, sm.Ly, sm.Rx, sm.Ry)
+			log.Printf("JS: Lx: %s, Ly: %s, Rx: %s, Ry: %s\n",
+				testAxis(jsStates, mode.yaw, sm.Lx), testAxis(jsStates, mode.throttle, sm.Ly),
+				testAxis(jsStates, mode.roll, sm.Rx), testAxis(jsStates, mode.pitch, sm.Ry))
 		} else {
-			stickChan <- sm
-
+			// the trim is only added to what is sent, a centred stick still counts as centred
+			out := sm
+			out.Lx = trimYaw(out.Lx)
+			if sm != (tello.StickMessage{}) {
+				abortMacro()
+				cancelTakeOffHeight()
+				cancelPosHold()
+			}
+			if sm != (tello.StickMessage{}) || (!takeOffHeightActive() && !posHoldActive()) {
+				stickChan <- out
+			}
+			if sm != prevSm {
+				noteInput()
+				if jsLogChan != nil {
+					logSticks(out)
+				}
+			}
 		}
-
-		if jsState.Buttons&(1<<jsConfig.buttons[btnL1]) != 0 && prevState.Buttons&(1<<jsConfig.buttons[btnL1]) == 0 {
+		prevSm = sm
+
+		for btn, lines := range jsConfig.macros {
+			if buttonDown(jsStates, btn) && !buttonDown(prevStates, btn) {
+				if test {
+					log.Printf("%s pressed - macro %q\n", mappingName(buttonNames, btn), lines)
+				} else {
+					startMacro(mappingName(buttonNames, btn), lines)
+				}
+			}
+		}
+		if pressed(jsStates, prevStates, btnL1) {
 			if test {
 				log.Println("L1 pressed")
 			} else {
-				drone.Bounce()
+				toggleBounce()
 			}
 
 		}
-		if jsState.Buttons&(1<<jsConfig.buttons[btnL2]) != 0 && prevState.Buttons&(1<<jsConfig.buttons[btnL2]) == 0 {
+		if pressed(jsStates, prevStates, btnL2) {
 			if test {
 				log.Println("L2 pressed")
 			} else {
-				drone.PalmLand()
+				palmLand()
 			}
 
 		}
-		if jsState.Buttons&(1<<jsConfig.buttons[btnSquare]) != 0 && prevState.Buttons&(1<<jsConfig.buttons[btnSquare]) == 0 {
-			if test {
-				log.Println("Square pressed")
-			} else {
-				drone.TakePicture()
+		// holding R1 turns Triangle, Circle and Square into smart video buttons
+		if buttonDown(jsStates, btnR1) {
+			for _, sv := range smartVideoButtons {
+				if pressed(jsStates, prevStates, sv.btn) {
+					if test {
+						log.Printf("R1+%s pressed\n", sv.name)
+					} else {
+						startSmartVideo(sv.cmd)
+					}
+				}
 			}
+			if pressed(jsStates, prevStates, btnX) {
+				if test {
+					log.Println("R1+X pressed")
+				} else if err := startReturnAndLand(); err != nil {
+					showMessage(fmt.Sprintf("Cannot return and land - %v", err))
+				}
+			}
+			if pressed(jsStates, prevStates, btnL3) {
+				if test {
+					log.Println("R1+L3 pressed")
+				} else {
+					adjustYawTrim(-1)
+				}
+			}
+			if pressed(jsStates, prevStates, btnR3) {
+				if test {
+					log.Println("R1+R3 pressed")
+				} else {
+					adjustYawTrim(1)
+				}
+			}
+		} else {
+			if pressed(jsStates, prevStates, btnSquare) {
+				if test {
+					log.Println("Square pressed")
+				} else {
+					drone.TakePicture()
+				}
 
-		}
-		if jsState.Buttons&(1<<jsConfig.buttons[btnTriangle]) != 0 && prevState.Buttons&(1<<jsConfig.buttons[btnTriangle]) == 0 {
-			if test {
-				log.Println("Triangle pressed")
-			} else {
-				drone.TakeOff()
 			}
+			if pressed(jsStates, prevStates, btnTriangle) {
+				if test {
+					log.Println("Triangle pressed")
+				} else {
+					takeOff()
+				}
 
-		}
-		if jsState.Buttons&(1<<jsConfig.buttons[btnCircle]) != 0 && prevState.Buttons&(1<<jsConfig.buttons[btnCircle]) == 0 {
-			if test {
-				log.Println("Circle pressed")
 			}
-		}
-		if jsState.Buttons&(1<<jsConfig.buttons[btnX]) != 0 && prevState.Buttons&(1<<jsConfig.buttons[btnX]) == 0 {
-			if test {
-				log.Println("X pressed")
-			} else {
-				drone.Land()
+			if pressed(jsStates, prevStates, btnCircle) {
+				if test {
+					log.Println("Circle pressed")
+				} else {
+					toggleVideo()
+				}
+			}
+			if pressed(jsStates, prevStates, btnX) {
+				if test {
+					log.Println("X pressed")
+				} else {
+					land()
+				}
+			}
+			if pressed(jsStates, prevStates, btnL3) {
+				if test {
+					log.Println("L3 pressed")
+				} else {
+					setFastMode(false)
+				}
+			}
+			if pressed(jsStates, prevStates, btnR3) {
+				if test {
+					log.Println("R3 pressed")
+				} else {
+					setFastMode(true)
+				}
 			}
 		}
-		prevState = jsState
+		prevStates = jsStates
 
-		time.Sleep(updatePeriodMs)
+		time.Sleep(updatePeriodMs * time.Millisecond)
 	}
 }
@@ -0,0 +1,131 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// names used for axes and buttons in -jsconfig files
+var axisNames = map[string]int{
+	"LeftX": axLeftX, "LeftY": axLeftY, "RightX": axRightX, "RightY": axRightY,
+	"L1": axL1, "L2": axL2, "R1": axR1, "R2": axR2,
+}
+
+var buttonNames = map[string]int{
+	"X": btnX, "Circle": btnCircle, "Triangle": btnTriangle, "Square": btnSquare,
+	"L1": btnL1, "L2": btnL2, "L3": btnL3, "R1": btnR1, "R2": btnR2, "R3": btnR3,
+}
+
+// jsConfigFile is the JSON form of a joystickConfig, e.g.
+//
+//	{ "axes":    { "LeftX": 0, "LeftY": 1, "RightX": 3, "RightY": 4 },
+//	  "buttons": { "X": 0, "Circle": 1, "Triangle": 2, "Square": 3 } }
+//
+// Entries which are not given keep the value from the -jstype mapping.
+// When -jsid lists several devices "axisdevices" and "buttondevices" say
+// which one (counting from 0 in -jsid order) an axis or button is on, e.g.
+//
+//	"axisdevices": { "LeftX": 1, "LeftY": 1 }
+//
+// takes the left stick axes from the second device.
+type jsConfigFile struct {
+	Axes       map[string]int      `json:"axes"`
+	Buttons    map[string]uint     `json:"buttons"`
+	AxisDevs   map[string]int      `json:"axisdevices,omitempty"`
+	ButtonDevs map[string]int      `json:"buttondevices,omitempty"`
+	Macros     map[string][]string `json:"macros,omitempty"` // see macro.go
+}
+
+// loadJoystickConfig overlays the mappings in the given file onto base
+func loadJoystickConfig(path string, base joystickConfig) (joystickConfig, error) {
+	var jcf jsConfigFile
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return base, err
+	}
+	if err = json.Unmarshal(buf, &jcf); err != nil {
+		return base, err
+	}
+	// copy so that the built-in configs are never modified
+	conf := joystickConfig{
+		axes:       make([]int, axR2+1),
+		buttons:    make([]uint, btnUnknown),
+		axisDevs:   make([]int, axR2+1),
+		buttonDevs: make([]int, btnUnknown),
+	}
+	for ax := range conf.axes {
+		conf.axes[ax] = -1 // unmapped unless base or the file says otherwise
+	}
+	for btn := range conf.buttons {
+		conf.buttons[btn] = btnUnmapped
+	}
+	copy(conf.axes, base.axes)
+	copy(conf.buttons, base.buttons)
+	copy(conf.axisDevs, base.axisDevs)
+	copy(conf.buttonDevs, base.buttonDevs)
+	for name, ix := range jcf.Axes {
+		ax, ok := axisNames[name]
+		if !ok {
+			return base, fmt.Errorf("unknown axis name <%s>", name)
+		}
+		conf.axes[ax] = ix
+	}
+	for name, ix := range jcf.Buttons {
+		btn, ok := buttonNames[name]
+		if !ok {
+			return base, fmt.Errorf("unknown button name <%s>", name)
+		}
+		conf.buttons[btn] = ix
+	}
+	for name, dev := range jcf.AxisDevs {
+		ax, ok := axisNames[name]
+		if !ok {
+			return base, fmt.Errorf("unknown axis name <%s>", name)
+		}
+		conf.axisDevs[ax] = dev
+	}
+	for name, dev := range jcf.ButtonDevs {
+		btn, ok := buttonNames[name]
+		if !ok {
+			return base, fmt.Errorf("unknown button name <%s>", name)
+		}
+		conf.buttonDevs[btn] = dev
+	}
+	for name, lines := range jcf.Macros {
+		btn, ok := buttonNames[name]
+		if !ok {
+			return base, fmt.Errorf("unknown button name <%s> for macro", name)
+		}
+		if err := checkMacro(lines); err != nil {
+			return base, fmt.Errorf("bad %s macro %v", name, err)
+		}
+		if conf.macros == nil {
+			conf.macros = map[int][]string{}
+		}
+		conf.macros[btn] = lines
+	}
+	return conf, nil
+}
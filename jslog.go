@@ -0,0 +1,107 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"encoding/csv"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/SMerrony/tello"
+)
+
+type jsLogEntry struct {
+	t  time.Time
+	sm tello.StickMessage
+}
+
+var (
+	jsLogChan chan jsLogEntry // never closed as the joystick loop may still be sending
+	jsLogStop chan struct{}
+	jsLogDone chan struct{}
+)
+
+// startJSLog opens a CSV file recording every change of stick values sent to the drone
+func startJSLog(path string) {
+	f, err := os.Create(path)
+	if err != nil {
+		log.Fatal("Cannot create joystick log file: ", err)
+	}
+	w := csv.NewWriter(f)
+	if err = w.Write([]string{"Time", "Lx", "Ly", "Rx", "Ry"}); err != nil {
+		log.Fatal("Cannot write headers to joystick log file: ", err)
+	}
+	jsLogChan = make(chan jsLogEntry, 100)
+	jsLogStop = make(chan struct{})
+	jsLogDone = make(chan struct{})
+	write := func(e jsLogEntry) {
+		w.Write([]string{logTime(e.t),
+			strconv.Itoa(int(e.sm.Lx)), strconv.Itoa(int(e.sm.Ly)),
+			strconv.Itoa(int(e.sm.Rx)), strconv.Itoa(int(e.sm.Ry))})
+	}
+	go func() {
+		flushTicker := time.NewTicker(time.Second)
+		defer func() {
+			flushTicker.Stop()
+			w.Flush()
+			f.Close()
+			close(jsLogDone)
+		}()
+		for {
+			select {
+			case e := <-jsLogChan:
+				write(e)
+			case <-jsLogStop:
+				for { // drain whatever was queued before the stop
+					select {
+					case e := <-jsLogChan:
+						write(e)
+					default:
+						return
+					}
+				}
+			case <-flushTicker.C:
+				w.Flush()
+			}
+		}
+	}()
+}
+
+// logSticks queues a stick message for logging, it never blocks the joystick loop
+func logSticks(sm tello.StickMessage) {
+	select {
+	case jsLogChan <- jsLogEntry{time.Now(), sm}:
+	default:
+	}
+}
+
+// stopJSLog writes out any queued entries and closes the log
+func stopJSLog() {
+	if jsLogChan == nil {
+		return
+	}
+	close(jsLogStop)
+	<-jsLogDone
+}
@@ -0,0 +1,112 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build sdl
+// +build sdl
+
+package main
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/simulatedsimian/joystick"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// The SDL2 backend reads game controllers through SDL's controller database, so
+// that sticks and buttons arrive in the same places whatever the pad or OS.
+// It satisfies joystick.Joystick so readJoystick need not know which is in use.
+
+const sdlTriggerPressed = 16384 // half travel on a trigger counts as L2/R2 down
+
+var (
+	sdlInitOnce sync.Once
+	sdlInitErr  error
+	sdlMu       sync.Mutex // SDL is not safe for concurrent use
+)
+
+type sdlJoystick struct {
+	gc *sdl.GameController
+}
+
+func openSDLJoystick(id int) (joystick.Joystick, error) {
+	sdlInitOnce.Do(func() {
+		// we have no window, so controller events must not depend on focus
+		sdl.SetHint(sdl.HINT_JOYSTICK_ALLOW_BACKGROUND_EVENTS, "1")
+		sdlInitErr = sdl.Init(sdl.INIT_GAMECONTROLLER)
+	})
+	if sdlInitErr != nil {
+		return nil, sdlInitErr
+	}
+	sdlMu.Lock()
+	defer sdlMu.Unlock()
+	if id >= sdl.NumJoysticks() {
+		return nil, fmt.Errorf("no SDL joystick %d", id)
+	}
+	if !sdl.IsGameController(id) {
+		return nil, fmt.Errorf("SDL has no game controller mapping for joystick %d", id)
+	}
+	gc := sdl.GameControllerOpen(id)
+	if gc == nil {
+		return nil, sdl.GetError()
+	}
+	return &sdlJoystick{gc}, nil
+}
+
+func (j *sdlJoystick) AxisCount() int { return int(sdl.CONTROLLER_AXIS_MAX) }
+
+func (j *sdlJoystick) ButtonCount() int { return sdlButtonR2 + 1 }
+
+func (j *sdlJoystick) Name() string { return j.gc.Name() }
+
+func (j *sdlJoystick) Read() (joystick.State, error) {
+	sdlMu.Lock()
+	defer sdlMu.Unlock()
+	var st joystick.State
+	sdl.GameControllerUpdate()
+	if !j.gc.Attached() {
+		return st, fmt.Errorf("%s is disconnected", j.gc.Name())
+	}
+	st.AxisData = make([]int, sdl.CONTROLLER_AXIS_MAX)
+	for ax := sdl.GameControllerAxis(0); ax < sdl.CONTROLLER_AXIS_MAX; ax++ {
+		st.AxisData[ax] = int(j.gc.Axis(ax))
+	}
+	for btn := sdl.GameControllerButton(0); btn < sdl.CONTROLLER_BUTTON_MAX && btn < sdlButtonL2; btn++ {
+		if j.gc.Button(btn) != 0 {
+			st.Buttons |= 1 << uint(btn)
+		}
+	}
+	if st.AxisData[sdl.CONTROLLER_AXIS_TRIGGERLEFT] > sdlTriggerPressed {
+		st.Buttons |= 1 << sdlButtonL2
+	}
+	if st.AxisData[sdl.CONTROLLER_AXIS_TRIGGERRIGHT] > sdlTriggerPressed {
+		st.Buttons |= 1 << sdlButtonR2
+	}
+	return st, nil
+}
+
+func (j *sdlJoystick) Close() {
+	sdlMu.Lock()
+	j.gc.Close()
+	sdlMu.Unlock()
+}
@@ -0,0 +1,196 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/simulatedsimian/joystick"
+)
+
+// The joystick wizard asks for each control to be moved in turn, watches which
+// axis or button changes, and writes the result as a -jsconfig file.
+
+const (
+	wizardWait      = 10 * time.Second // time allowed for each control, after which it is skipped
+	wizardAxisMoved = 16000            // change from rest that counts as a deliberate movement
+	wizardPoll      = 20 * time.Millisecond
+)
+
+var wizardAxes = []struct {
+	name   string
+	prompt string
+	up     bool // pushed forward, which should read negative
+}{
+	{"LeftX", "Push the LEFT stick fully RIGHT", false},
+	{"LeftY", "Push the LEFT stick fully UP (forward)", true},
+	{"RightX", "Push the RIGHT stick fully RIGHT", false},
+	{"RightY", "Push the RIGHT stick fully UP (forward)", true},
+	{"L2", "Pull the LEFT analog trigger (L2) fully, if it has one", false},
+	{"R2", "Pull the RIGHT analog trigger (R2) fully, if it has one", false},
+}
+
+var wizardButtons = []string{"X", "Circle", "Triangle", "Square", "L1", "L2", "L3", "R1", "R2", "R3"}
+
+// wizardRead reads every device, giving up on errors as the wizard cannot continue
+func wizardRead() []joystick.State {
+	sts, err := readJoysticks()
+	if err != nil {
+		log.Fatalf("Error reading joystick: %v\n", err)
+	}
+	return sts
+}
+
+// wizardAxis waits for an axis to move well away from its rest reading
+func wizardAxis(rest []joystick.State) (dev, ax, delta int, ok bool) {
+	for start := time.Now(); time.Since(start) < wizardWait; time.Sleep(wizardPoll) {
+		best := 0
+		for d, st := range wizardRead() {
+			for a, v := range st.AxisData {
+				if a >= len(rest[d].AxisData) {
+					continue
+				}
+				if diff := v - rest[d].AxisData[a]; absInt(diff) > absInt(best) {
+					dev, ax, best = d, a, diff
+				}
+			}
+		}
+		if absInt(best) > wizardAxisMoved {
+			return dev, ax, best, true
+		}
+	}
+	return 0, 0, 0, false
+}
+
+// wizardButton waits for a button that is not held at rest to be pressed
+func wizardButton(rest []joystick.State) (dev int, btn uint, ok bool) {
+	for start := time.Now(); time.Since(start) < wizardWait; time.Sleep(wizardPoll) {
+		for d, st := range wizardRead() {
+			if down := st.Buttons &^ rest[d].Buttons; down != 0 {
+				for b := uint(0); b < 32; b++ {
+					if down&(1<<b) != 0 {
+						return d, b, true
+					}
+				}
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// wizardRelease waits until the controls are back at rest
+func wizardRelease(rest []joystick.State) {
+	for start := time.Now(); time.Since(start) < wizardWait; time.Sleep(wizardPoll) {
+		settled := true
+		for d, st := range wizardRead() {
+			if st.Buttons != rest[d].Buttons {
+				settled = false
+			}
+			for a, v := range st.AxisData {
+				if a < len(rest[d].AxisData) && absInt(v-rest[d].AxisData[a]) > wizardAxisMoved/2 {
+					settled = false
+				}
+			}
+		}
+		if settled {
+			return
+		}
+	}
+}
+
+func runJoystickWizard(ids string) {
+	openJoysticks(ids)
+	in := bufio.NewReader(os.Stdin)
+	for i, js := range jss {
+		fmt.Printf("Device %d: %s, %d axes, %d buttons\n", i, js.Name(), js.AxisCount(), js.ButtonCount())
+	}
+	fmt.Printf("Each control is skipped if nothing happens within %v.\n", wizardWait)
+	fmt.Print("Leave all the sticks centred and the triggers released, then press Enter...")
+	in.ReadString('\n')
+	rest := wizardRead()
+
+	jcf := jsConfigFile{
+		Axes:    map[string]int{},
+		Buttons: map[string]uint{},
+	}
+	if len(jss) > 1 {
+		jcf.AxisDevs, jcf.ButtonDevs = map[string]int{}, map[string]int{}
+	}
+	for _, a := range wizardAxes {
+		fmt.Printf("%s... ", a.prompt)
+		dev, ax, delta, ok := wizardAxis(rest)
+		if !ok {
+			fmt.Println("skipped")
+			continue
+		}
+		fmt.Printf("axis %d", ax)
+		if len(jss) > 1 {
+			fmt.Printf(" on device %d", dev)
+			jcf.AxisDevs[a.name] = dev
+		}
+		fmt.Println()
+		if a.up && delta > 0 {
+			fmt.Println("  Warning: this axis reads positive when pushed forward, telloterm expects negative so it would work backwards")
+		}
+		jcf.Axes[a.name] = ax
+		fmt.Println("  Now let go")
+		wizardRelease(rest)
+	}
+	for _, name := range wizardButtons {
+		fmt.Printf("Press the button to use as %s... ", name)
+		dev, btn, ok := wizardButton(rest)
+		if !ok {
+			fmt.Println("skipped")
+			continue
+		}
+		fmt.Printf("button %d", btn)
+		if len(jss) > 1 {
+			fmt.Printf(" on device %d", dev)
+			jcf.ButtonDevs[name] = dev
+		}
+		fmt.Println()
+		jcf.Buttons[name] = btn
+		wizardRelease(rest)
+	}
+
+	buf, err := json.MarshalIndent(jcf, "", "  ")
+	if err != nil {
+		log.Fatalf("Cannot encode the joystick config - %v\n", err)
+	}
+	fmt.Printf("\n%s\n\nSave this to file (Enter to skip): ", buf)
+	path, _ := in.ReadString('\n')
+	if path = strings.TrimSpace(path); path == "" {
+		return
+	}
+	if err = ioutil.WriteFile(path, append(buf, '\n'), 0644); err != nil {
+		log.Fatalf("Cannot write %s - %v\n", path, err)
+	}
+	fmt.Printf("Saved, use it with -jsid %s -jsconfig %s\n", ids, path)
+}
@@ -0,0 +1,94 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"math"
+	"time"
+
+	"github.com/nsf/termbox-go"
+)
+
+// With -diagonal each arrow key sets the whole horizontal direction of travel,
+// and two perpendicular arrows pressed within diagWindow of each other combine
+// into a diagonal.  Without it each arrow only changes its own axis, as before.
+
+const diagWindow = 300 * time.Millisecond
+
+var (
+	lastArrow   termbox.Key // only used by the keyboard goroutine
+	lastArrowAt time.Time
+)
+
+// arrowDir gives the forward and right components of an arrow key
+func arrowDir(k termbox.Key) (fwd, rgt int) {
+	switch k {
+	case termbox.KeyArrowUp:
+		return 1, 0
+	case termbox.KeyArrowDown:
+		return -1, 0
+	case termbox.KeyArrowLeft:
+		return 0, -1
+	case termbox.KeyArrowRight:
+		return 0, 1
+	}
+	return 0, 0
+}
+
+func arrowKey(k termbox.Key) {
+	fwd, rgt := arrowDir(k)
+	if !*diagonalFlag {
+		switch {
+		case fwd > 0:
+			drone.Forward(keyPct)
+		case fwd < 0:
+			drone.Backward(keyPct)
+		case rgt < 0:
+			drone.Left(keyPct)
+		case rgt > 0:
+			drone.Right(keyPct)
+		}
+		return
+	}
+	if time.Since(lastArrowAt) < diagWindow {
+		pf, pr := arrowDir(lastArrow)
+		if (pf != 0) != (fwd != 0) { // perpendicular
+			fwd, rgt = fwd+pf, rgt+pr
+		}
+	}
+	lastArrow, lastArrowAt = k, time.Now()
+	pct := keyPct
+	if fwd != 0 && rgt != 0 {
+		pct = int(math.Round(keyPct / math.Sqrt2)) // same overall speed on a diagonal
+	}
+	if fwd >= 0 {
+		drone.Forward(fwd * pct)
+	} else {
+		drone.Backward(-fwd * pct)
+	}
+	if rgt >= 0 {
+		drone.Right(rgt * pct)
+	} else {
+		drone.Left(-rgt * pct)
+	}
+}
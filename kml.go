@@ -0,0 +1,130 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/SMerrony/tello"
+)
+
+const (
+	metresPerDegLat = 111320.0 // near enough everywhere for a short flight
+	kmlMinStep      = 0.1      // metres moved before a new path point is recorded
+)
+
+type kmlPoint struct {
+	lat, lon, alt float64
+}
+
+var (
+	kmlMu     sync.Mutex
+	kmlPath   []kmlPoint
+	kmlLat0   float64 // where MVO 0,0 is put on the globe
+	kmlLon0   float64
+	kmlLastX  float32
+	kmlLastY  float32
+	kmlLastHt int16
+)
+
+// setupKML reads -kmlhome, without it the path starts at 0,0 in the Atlantic
+func setupKML() error {
+	if *kmlHomeFlag == "" {
+		return nil
+	}
+	parts := strings.Split(*kmlHomeFlag, ",")
+	if len(parts) != 2 {
+		return fmt.Errorf("-kmlhome must be latitude,longitude")
+	}
+	var err error
+	if kmlLat0, err = strconv.ParseFloat(strings.TrimSpace(parts[0]), 64); err != nil {
+		return err
+	}
+	kmlLon0, err = strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	return err
+}
+
+// addKMLPoint converts the MVO position to latitude and longitude, taking
+// the X axis (yaw 0) as north, and adds it to the path if the drone has moved
+func addKMLPoint(fd tello.FlightData) {
+	if !fd.Flying {
+		return
+	}
+	kmlMu.Lock()
+	defer kmlMu.Unlock()
+	x, y := fd.MVO.PositionX, fd.MVO.PositionY
+	if len(kmlPath) > 0 && fd.Height == kmlLastHt &&
+		math.Hypot(float64(x-kmlLastX), float64(y-kmlLastY)) < kmlMinStep {
+		return
+	}
+	kmlLastX, kmlLastY, kmlLastHt = x, y, fd.Height
+	kmlPath = append(kmlPath, kmlPoint{
+		lat: kmlLat0 + float64(x)/metresPerDegLat,
+		lon: kmlLon0 + float64(y)/(metresPerDegLat*math.Cos(kmlLat0*math.Pi/180)),
+		alt: float64(fd.Height) / 10,
+	})
+}
+
+// writeKML saves the path as a LineString, it does nothing if there is no path
+func writeKML(path string) error {
+	kmlMu.Lock()
+	defer kmlMu.Unlock()
+	if len(kmlPath) == 0 {
+		return nil
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+<kml xmlns="http://www.opengis.net/kml/2.2">
+<Document>
+<name>Tello flight</name>
+<Placemark>
+<name>Flight path</name>
+<LineString>
+<altitudeMode>relativeToGround</altitudeMode>
+<coordinates>
+`)
+	for _, p := range kmlPath {
+		fmt.Fprintf(w, "%.8f,%.8f,%.1f\n", p.lon, p.lat, p.alt)
+	}
+	fmt.Fprint(w, `</coordinates>
+</LineString>
+</Placemark>
+</Document>
+</kml>
+`)
+	if err = w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
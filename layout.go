@@ -0,0 +1,134 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"log"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const compactRows = 20 // fields per column in a compact layout
+
+// the terminal size needed by the current layout
+var (
+	reqWidth  = minWidth
+	reqHeight = minHeight
+)
+
+// hidden marks fields not drawn in a compact layout
+var hidden [fNumFields]bool
+
+var compact bool // true when -fields has replaced the standard layout
+
+// fieldName derives the name used by -fields from a field's label,
+// e.g. "Max Height:" becomes "maxheight"
+func fieldName(f int) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, fields[f].lab.text)
+}
+
+func fieldNames() (names []string) {
+	for f := range fields {
+		if n := fieldName(f); n != "" {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// packFields replaces the standard layout with just the named fields, stacked
+// in columns below the title, and shrinks the required terminal size to suit
+func packFields(list string) {
+	byName := map[string]int{}
+	for f := range fields {
+		if n := fieldName(f); n != "" {
+			byName[n] = f
+		}
+	}
+	var chosen []int
+	for _, n := range strings.Split(list, ",") {
+		f, ok := byName[strings.ToLower(strings.TrimSpace(n))]
+		if !ok {
+			log.Fatalf("Unknown field <%s> for -fields, choose from: %s", n, strings.Join(fieldNames(), ","))
+		}
+		chosen = append(chosen, f)
+	}
+
+	compact = true
+	for f := range hidden {
+		hidden[f] = true
+	}
+	hidden[fMessage] = false
+
+	x, rows := 0, 0
+	for start := 0; start < len(chosen); start += compactRows {
+		end := start + compactRows
+		if end > len(chosen) {
+			end = len(chosen)
+		}
+		labW, valW := 0, 0
+		for _, f := range chosen[start:end] {
+			if l := len(fields[f].lab.text); l > labW {
+				labW = l
+			}
+			if fields[f].w > valW {
+				valW = fields[f].w
+			}
+		}
+		for i, f := range chosen[start:end] {
+			fields[f].lab.x = x + labW - len(fields[f].lab.text)
+			fields[f].lab.y = i + 2
+			fields[f].x = x + labW + 1
+			fields[f].y = i + 2
+			hidden[f] = false
+		}
+		if end-start > rows {
+			rows = end - start
+		}
+		x += labW + valW + 3
+	}
+
+	staticLabels = staticLabels[:1] // keep only the title
+	staticLabels[0].x = 0
+	reqWidth = x
+	titleW := len(staticLabels[0].text) // including the tags drawn after it
+	if *simFlag {
+		titleW += 12
+	}
+	if *readOnlyFlag {
+		titleW += 12
+	}
+	if reqWidth < titleW {
+		reqWidth = titleW
+	}
+	reqHeight = rows + 4
+	fields[fMessage].y = reqHeight - 1
+	fields[fMessage].w = reqWidth - 1
+}
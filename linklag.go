@@ -0,0 +1,91 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nsf/termbox-go"
+)
+
+// The link lag is the average gap between changes in the flight data over the
+// last few seconds.  A healthy link gives a steady stream of fresh data, so a
+// growing gap is an early sign of a sluggish link, well before it is LOST.
+// It cannot be better than updatePeriodMs as that is how often we look.
+
+const (
+	lagWindow  = 3 * time.Second
+	lagCaution = 300 * time.Millisecond
+	lagBad     = time.Second
+)
+
+var lagChanges []time.Time // when the flight data changed within lagWindow, guarded by fieldsMu
+
+// noteDataChange records a change in the flight data, fieldsMu must be held
+func noteDataChange(now time.Time) {
+	lagChanges = append(lagChanges, now)
+	i := 0
+	for i < len(lagChanges)-1 && now.Sub(lagChanges[i]) > lagWindow {
+		i++
+	}
+	lagChanges = lagChanges[i:]
+}
+
+// linkLag is the average gap between data changes, including the time since
+// the latest one so that a stall shows up at once, fieldsMu must be read-locked
+func linkLag(now time.Time) (lag time.Duration, ok bool) {
+	n := len(lagChanges)
+	if n == 0 {
+		return 0, false
+	}
+	gaps := n - 1
+	total := lagChanges[n-1].Sub(lagChanges[0])
+	if since := now.Sub(lagChanges[n-1]); since > updatePeriodMs*time.Millisecond {
+		gaps++
+		total += since
+	}
+	if gaps == 0 {
+		return 0, false
+	}
+	return total / time.Duration(gaps), true
+}
+
+// showLinkLag updates the Lag field, fieldsMu must be held
+func showLinkLag(now time.Time) {
+	lag, ok := linkLag(now)
+	if !ok {
+		fields[fLinkLag].value = "?"
+		fields[fLinkLag].fg = th.Value
+		return
+	}
+	fields[fLinkLag].value = fmt.Sprintf("%dms", lag/time.Millisecond)
+	switch {
+	case lag >= lagBad:
+		fields[fLinkLag].fg = th.Bad | termbox.AttrBold
+	case lag >= lagCaution:
+		fields[fLinkLag].fg = th.Caution
+	default:
+		fields[fLinkLag].fg = th.Good
+	}
+}
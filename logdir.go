@@ -0,0 +1,121 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// With -logdir each run gets its own timestamped subdirectory holding the
+// flight log, KML track and pictures, plus a session.json describing the flight.
+// Explicit -fdlog or -kml files are left where the user asked for them.
+
+const sessionFile = "session.json"
+
+var (
+	sessionDir   string // empty unless -logdir is in use
+	sessionStart time.Time
+)
+
+// sessionInfo is written to session.json when the program exits
+type sessionInfo struct {
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Duration float64   `json:"durationSecs"`
+	SSID     string    `json:"ssid,omitempty"`
+	Version  string    `json:"version,omitempty"`
+	Files    []string  `json:"files"`
+}
+
+// setupLogDir creates this session's directory under dir and points the
+// flight log and KML output into it unless they have been set explicitly
+func setupLogDir(dir string) error {
+	sessionStart = time.Now()
+	sessionDir = filepath.Join(dir, sessionStart.Format("20060102-150405"))
+	if err := os.MkdirAll(sessionDir, 0755); err != nil {
+		return err
+	}
+	if *fdLogFlag == "" {
+		name := "flight.csv"
+		if *fdLogFmtFlag == "json" {
+			name = "flight.json"
+		}
+		*fdLogFlag = filepath.Join(sessionDir, name)
+	}
+	if *kmlFlag == "" {
+		*kmlFlag = filepath.Join(sessionDir, "flight.kml")
+	}
+	return nil
+}
+
+// picPrefix is the file name prefix for pictures saved at exit, they go in
+// the -picdir if given, else the session directory, else the current directory
+func picPrefix() (string, error) {
+	prefix := fmt.Sprintf("tello_pic_%s", time.Now().Format(time.RFC3339))
+	switch {
+	case *picDirFlag != "":
+		if err := os.MkdirAll(*picDirFlag, 0755); err != nil {
+			return "", err
+		}
+		prefix = filepath.Join(*picDirFlag, prefix)
+	case sessionDir != "":
+		prefix = filepath.Join(sessionDir, prefix)
+	}
+	return prefix, nil
+}
+
+// writeSessionInfo records the session metadata and an index of the files
+// in the session directory, it should be called once everything else is saved
+func writeSessionInfo() error {
+	if sessionDir == "" {
+		return nil
+	}
+	fd := currentFd()
+	info := sessionInfo{
+		Start:   sessionStart,
+		End:     time.Now(),
+		SSID:    fd.SSID,
+		Version: fd.Version,
+		Files:   []string{},
+	}
+	info.Duration = info.End.Sub(info.Start).Round(time.Second).Seconds()
+	entries, err := ioutil.ReadDir(sessionDir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.Name() != sessionFile {
+			info.Files = append(info.Files, e.Name())
+		}
+	}
+	b, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(sessionDir, sessionFile), append(b, '\n'), 0644)
+}
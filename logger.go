@@ -0,0 +1,84 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+)
+
+// Messages about what telloterm is doing go through a levelled logger, to
+// -logfile if given, otherwise to stderr as before.  By default debug messages
+// are dropped, -verbose keeps them and -quiet drops everything below an error.
+// Fatal startup errors still use the standard log so that they are always seen.
+
+type logLevel int
+
+const (
+	levelDebug logLevel = iota
+	levelInfo
+	levelWarn
+	levelError
+)
+
+var levelNames = [...]string{"DEBUG", "INFO ", "WARN ", "ERROR"}
+
+var (
+	lg       = log.New(os.Stderr, "", log.LstdFlags)
+	minLevel = levelInfo // messages below this are dropped
+)
+
+// setupLogging applies -logfile, -verbose and -quiet
+func setupLogging() error {
+	if *verboseFlag && *quietFlag {
+		return errors.New("-verbose and -quiet cannot both be used")
+	}
+	switch {
+	case *verboseFlag:
+		minLevel = levelDebug
+	case *quietFlag:
+		minLevel = levelError
+	}
+	if *logFileFlag != "" {
+		f, err := os.OpenFile(*logFileFlag, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+		if err != nil {
+			return err
+		}
+		lg.SetOutput(f)
+	}
+	return nil
+}
+
+func logAt(level logLevel, format string, v ...interface{}) {
+	if level < minLevel {
+		return
+	}
+	lg.Output(3, levelNames[level]+" "+fmt.Sprintf(format, v...))
+}
+
+func logDebug(format string, v ...interface{}) { logAt(levelDebug, format, v...) }
+func logInfo(format string, v ...interface{})  { logAt(levelInfo, format, v...) }
+func logWarn(format string, v ...interface{})  { logAt(levelWarn, format, v...) }
+func logError(format string, v ...interface{}) { logAt(levelError, format, v...) }
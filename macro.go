@@ -0,0 +1,130 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A joystick macro is a list of commands, as used by scripts, run when a button
+// is pressed.  Macros are given in the -jsconfig file, e.g.
+//
+//	"macros": { "Square": ["takeoff", "wait 5", "360"] }
+//
+// A button with a macro loses its usual action.  Only one macro runs at a time
+// and moving a stick aborts it, stopping at the next command or during a wait.
+
+var (
+	macroMu    sync.Mutex
+	macroAbort chan struct{} // non-nil while a macro is running
+)
+
+// checkMacro makes sure that every line of a macro is a valid command
+func checkMacro(lines []string) error {
+	for _, line := range lines {
+		if _, _, err := parseCommand(line); err != nil {
+			return fmt.Errorf("<%s> - %v", line, err)
+		}
+	}
+	return nil
+}
+
+// startMacro runs the macro in the background unless one is already running
+func startMacro(name string, lines []string) {
+	macroMu.Lock()
+	defer macroMu.Unlock()
+	if macroAbort != nil {
+		showMessage("A macro is already running")
+		return
+	}
+	abort := make(chan struct{})
+	macroAbort = abort
+	go func() {
+		err := runMacro(lines, abort)
+		macroMu.Lock()
+		if macroAbort == abort {
+			macroAbort = nil
+		}
+		macroMu.Unlock()
+		switch {
+		case err == errMacroAborted:
+			showMessage(fmt.Sprintf("%s macro aborted", name))
+		case err != nil:
+			logError("%s macro stopped - %v", name, err)
+			showMessage(fmt.Sprintf("%s macro stopped - %v", name, err))
+		default:
+			showMessage(fmt.Sprintf("%s macro done", name))
+		}
+	}()
+	showMessage(fmt.Sprintf("%s macro running - move a stick to abort", name))
+}
+
+var errMacroAborted = errors.New("aborted")
+
+// runMacro executes the lines in turn, waits are done here so that they can be aborted
+func runMacro(lines []string, abort chan struct{}) error {
+	for _, line := range lines {
+		select {
+		case <-abort:
+			return errMacroAborted
+		default:
+		}
+		if words := strings.Fields(line); len(words) == 2 && strings.ToLower(words[0]) == "wait" {
+			secs, err := strconv.ParseFloat(words[1], 64)
+			if err != nil || secs < 0 {
+				return fmt.Errorf("bad number of seconds <%s>", words[1])
+			}
+			select {
+			case <-abort:
+				return errMacroAborted
+			case <-time.After(time.Duration(secs * float64(time.Second))):
+			}
+			continue
+		}
+		if err := runCommand(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func macroRunning() bool {
+	macroMu.Lock()
+	defer macroMu.Unlock()
+	return macroAbort != nil
+}
+
+// abortMacro stops any running macro
+func abortMacro() {
+	macroMu.Lock()
+	if macroAbort != nil {
+		close(macroAbort)
+		macroAbort = nil
+	}
+	macroMu.Unlock()
+}
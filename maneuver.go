@@ -0,0 +1,93 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/SMerrony/tello"
+)
+
+// the drone doesn't report progress through these, so their lengths are estimates
+const (
+	throwTakeoffTime = 5 * time.Second  // how long the drone waits to be thrown
+	smartVideoTime   = 15 * time.Second // assumed length of a smart video flight
+)
+
+var smartVideoNames = map[tello.SvCmd]string{
+	tello.Sv360:    "360 smart video",
+	tello.SvCircle: "Circle smart video",
+	tello.SvUpOut:  "Up and out smart video",
+}
+
+// the special manoeuvre in progress, guarded by fieldsMu
+var (
+	maneuver    string // "" when there is none
+	maneuverEnd time.Time
+)
+
+// startManeuver shows name with a countdown until it is expected to finish,
+// it returns false, doing nothing, if another manoeuvre is still running
+func startManeuver(name string, length time.Duration) bool {
+	fieldsMu.Lock()
+	defer fieldsMu.Unlock()
+	if maneuver != "" {
+		return false
+	}
+	maneuver, maneuverEnd = name, time.Now().Add(length)
+	return true
+}
+
+// updateManeuver is called from updateFields with fieldsMu held, the manoeuvre
+// is over when its time is up or the drone takes off or lands
+func updateManeuver(newFd tello.FlightData, now time.Time) {
+	left := maneuverEnd.Sub(now)
+	if maneuver == "" || left <= 0 || newFd.Flying != prevFd.Flying {
+		maneuver = ""
+		fields[fManeuver].value = ""
+		return
+	}
+	fields[fManeuver].value = fmt.Sprintf("%s - %.0fs", maneuver, math.Ceil(left.Seconds()))
+}
+
+func throwTakeOff() error {
+	if err := checkPreflight(); err != nil {
+		return err
+	}
+	if startManeuver("Throw takeoff", throwTakeoffTime) {
+		noteTakeOff()
+		drone.ThrowTakeOff()
+	}
+	return nil
+}
+
+// startSmartVideo begins a smart video flight unless another manoeuvre is running
+func startSmartVideo(cmd tello.SvCmd) {
+	if !startManeuver(smartVideoNames[cmd], smartVideoTime) {
+		showMessage("Wait for the current manoeuvre to finish")
+		return
+	}
+	drone.StartSmartVideo(cmd)
+}
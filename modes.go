@@ -0,0 +1,49 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import "fmt"
+
+// flyModes names the FlyMode values, which follow the flight controller states
+// used across DJI aircraft, only a few of which a Tello ever reports
+var flyModes = map[uint8]string{
+	0: "Manual", 1: "Atti", 2: "Atti CL", 3: "Atti Hov", 4: "Hover",
+	5: "GPS Brk", 6: "GPS Atti", 7: "GPS CL", 8: "HomeLock", 9: "HotPoint",
+	10: "AssistTO", 11: "Takeoff", 12: "Landing", 13: "AttiLand", 14: "NaviGo",
+	15: "Go Home", 16: "ClickGo", 17: "Joystick", 23: "AttiLim", 24: "Draw",
+	25: "FollowMe", 26: "Track", 27: "TapFly", 28: "Pano", 31: "Sport",
+	32: "Novice", 33: "ConfLand", 36: "Palm", 37: "QuickSht", 41: "MotorsOn",
+}
+
+// cameraStates names the CameraState values seen so far
+var cameraStates = map[uint8]string{
+	0: "Normal",
+}
+
+// modeName looks up a mode, falling back to its number
+func modeName(names map[uint8]string, v uint8) string {
+	if s, ok := names[v]; ok {
+		return s
+	}
+	return fmt.Sprintf("%d", v)
+}
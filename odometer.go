@@ -0,0 +1,58 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import "math"
+
+// movements smaller than this (in MVO units, ~metres) are treated as jitter
+const odoMinStep = 0.05
+
+// odometer accumulates the straight-line distance travelled between MVO positions
+type odometer struct {
+	total               float64
+	anchorSet           bool
+	anchX, anchY, anchZ float64
+}
+
+var odo odometer
+
+func (o *odometer) reset() {
+	o.total = 0
+	o.anchorSet = false
+}
+
+func (o *odometer) update(x, y, z float32) {
+	fx, fy, fz := float64(x), float64(y), float64(z)
+	if !o.anchorSet {
+		o.anchX, o.anchY, o.anchZ = fx, fy, fz
+		o.anchorSet = true
+		return
+	}
+	dx, dy, dz := fx-o.anchX, fy-o.anchY, fz-o.anchZ
+	step := math.Sqrt(dx*dx + dy*dy + dz*dz)
+	if step < odoMinStep {
+		return
+	}
+	o.total += step
+	o.anchX, o.anchY, o.anchZ = fx, fy, fz
+}
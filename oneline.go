@@ -0,0 +1,95 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// -oneline replaces the panel with a single status line on stdout, rewritten
+// in place with a carriage return, e.g. for a tmux status bar.  It runs as
+// -headless does, taking commands from -script or stdin.
+
+var (
+	oneLineMu   sync.Mutex
+	oneLineStop chan struct{}
+	oneLineLen  int  // length of the last line, so that a shorter one can blank it out
+	oneLineEnd  bool // the line has been finished, nothing more is printed
+)
+
+// oneLineText is the status line, fieldsMu must be read-locked
+func oneLineText(now time.Time) string {
+	link := ""
+	if lastFdTime.IsZero() {
+		link = " | CONNECTING"
+	} else if now.Sub(lastFdTime) > linkTimeout {
+		link = " | LINK LOST"
+	}
+	return fmt.Sprintf("Batt %s | Ht %s | Spd %s | %s%s",
+		fields[fBattery].value, fields[fHeight].value, fields[fDerivedSpeed].value,
+		fields[fFlightState].value, link)
+}
+
+func startOneLine() {
+	oneLineStop = make(chan struct{})
+	go func() {
+		tick := time.NewTicker(updatePeriodMs * time.Millisecond)
+		defer tick.Stop()
+		for {
+			select {
+			case <-oneLineStop:
+				return
+			case now := <-tick.C:
+				fieldsMu.RLock()
+				line := oneLineText(now)
+				fieldsMu.RUnlock()
+				printOneLine(line)
+			}
+		}
+	}()
+}
+
+func printOneLine(line string) {
+	oneLineMu.Lock()
+	defer oneLineMu.Unlock()
+	if oneLineEnd {
+		return
+	}
+	fmt.Fprintf(os.Stdout, "\r%s", padString(line, oneLineLen))
+	oneLineLen = len(line)
+}
+
+// stopOneLine stops the updates and ends the line, it is safe to call if -oneline is not in use
+func stopOneLine() {
+	if oneLineStop == nil {
+		return
+	}
+	close(oneLineStop)
+	oneLineMu.Lock()
+	fmt.Fprintln(os.Stdout)
+	oneLineEnd = true
+	oneLineMu.Unlock()
+}
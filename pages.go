@@ -0,0 +1,116 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// display pages, Tab cycles through them
+const (
+	pageCockpit = iota
+	pageMap
+	pageDebug
+	numPages
+)
+
+const (
+	debugTop     = 2  // first row of the debug table
+	debugRows    = 20 // rows per column
+	debugColumns = 2
+	debugColW    = 39
+)
+
+var (
+	page        int // guarded by fieldsMu
+	debugScroll int // first row shown on the debug page
+)
+
+// nextPage switches to the next display page and redraws the screen
+func nextPage() {
+	fieldsMu.Lock()
+	page = (page + 1) % numPages
+	debugScroll = 0
+	fieldsMu.Unlock()
+	displayStaticFields()
+	displayDataFields()
+}
+
+// scrollDebug moves the debug table by the given number of rows
+func scrollDebug(rows int) {
+	fieldsMu.Lock()
+	defer fieldsMu.Unlock()
+	debugScroll += rows
+	if max := len(flatten("", reflect.ValueOf(prevFd), nil)) - debugRows*debugColumns; debugScroll > max {
+		debugScroll = max
+	}
+	if debugScroll < 0 {
+		debugScroll = 0
+	}
+}
+
+// flatten appends "Name value" for every field of v, descending into structs
+// whose fields are all exported, others (e.g. time.Time) are shown whole
+func flatten(prefix string, v reflect.Value, lines []string) []string {
+	t := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		name := prefix + t.Field(i).Name
+		fv := v.Field(i)
+		if !fv.CanInterface() {
+			continue
+		}
+		if _, ok := fv.Interface().(fmt.Stringer); !ok && fv.Kind() == reflect.Struct && allExported(fv.Type()) {
+			lines = flatten(name+".", fv, lines)
+		} else {
+			lines = append(lines, fmt.Sprintf("%-24s %v", name, fv.Interface()))
+		}
+	}
+	return lines
+}
+
+// allExported reports whether flatten can safely descend into struct type t
+func allExported(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).PkgPath != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// displayDebugPage draws every decoded FlightData value, fieldsMu must be read-locked
+func displayDebugPage() {
+	lines := flatten("", reflect.ValueOf(prevFd), nil)[debugScroll:]
+	perPage := debugRows * debugColumns
+	tbprint(0, 1, th.Heading, th.Background,
+		"Raw Flight Data (PgUp/PgDn to scroll, Tab for next page)")
+	for i := 0; i < perPage; i++ {
+		text := ""
+		if i < len(lines) {
+			text = lines[i]
+		}
+		x := (i / debugRows) * (debugColW + 1)
+		tbprint(x, debugTop+i%debugRows, th.Value, th.Background, padString(text, debugColW))
+	}
+}
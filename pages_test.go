@@ -0,0 +1,67 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SMerrony/tello"
+)
+
+func TestFlatten(t *testing.T) {
+	var fd tello.FlightData
+	fd.BatteryPercentage = 42
+	fd.MVO.PositionX = 1.5
+	fd.LightStrengthUpdated = time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC)
+	lines := flatten("", reflect.ValueOf(fd), nil)
+	want := map[string]string{
+		"BatteryPercentage":    "42",
+		"MVO.PositionX":        "1.5",
+		"LightStrengthUpdated": "2018-06-01 12:00:00 +0000 UTC",
+	}
+	for _, line := range lines {
+		name := strings.Fields(line)[0]
+		if w, ok := want[name]; ok {
+			if got := strings.TrimSpace(line[len(name):]); got != w {
+				t.Errorf("%s = %q, want %q", name, got, w)
+			}
+			delete(want, name)
+		}
+	}
+	for name := range want {
+		t.Errorf("no line for %s", name)
+	}
+
+	// unexported fields are skipped, structs with them are not descended into
+	lines = flatten("", reflect.ValueOf(struct {
+		A int
+		b int
+		T time.Time
+	}{A: 1, b: 2}), nil)
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "A ") || !strings.HasPrefix(lines[1], "T ") {
+		t.Errorf("got %q", lines)
+	}
+}
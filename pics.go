@@ -0,0 +1,65 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import "fmt"
+
+var (
+	picReport string // the outcome of savePics, shown by reportPics
+	picFailed bool
+)
+
+// savePics saves any pictures taken, the outcome is kept for reportPics as the
+// display is still up when this is called at exit
+func savePics() {
+	n := drone.NumPics()
+	if n == 0 {
+		return
+	}
+	saved := 0
+	prefix, err := picPrefix()
+	if err == nil {
+		saved, err = drone.SaveAllPics(prefix)
+	}
+	if err != nil {
+		picReport, picFailed = fmt.Sprintf("Could not save all the pictures, %d of %d saved - %v", saved, n, err), true
+	} else {
+		picReport = fmt.Sprintf("Saved %d pictures as %s*", saved, prefix)
+	}
+	if *headlessFlag { // there is no display to wait for
+		reportPics()
+	}
+}
+
+// reportPics logs the outcome of savePics, if there was one
+func reportPics() {
+	switch {
+	case picReport == "":
+		return
+	case picFailed:
+		logError("%s", picReport)
+	default:
+		logInfo("%s", picReport)
+	}
+	picReport = ""
+}
@@ -0,0 +1,124 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"errors"
+	"math"
+
+	"github.com/SMerrony/tello"
+)
+
+// Position hold is experimental.  The Tello drifts even when hovering, so this
+// captures the MVO position and then steers back towards it with small stick
+// movements in proportion to the error.  Any key or stick movement, losing
+// the ground visual or landing stops it.
+
+const (
+	holdDeadband = 0.1  // metres of error that are ignored
+	holdGain     = 0.3  // fraction of full stick per metre of error
+	holdMaxStick = 0.25 // never use more than this fraction of full stick
+)
+
+var (
+	holdActive bool // guarded by fieldsMu
+	holdX      float32
+	holdY      float32
+)
+
+// startPosHold captures the current position as the setpoint
+func startPosHold() error {
+	fieldsMu.Lock()
+	defer fieldsMu.Unlock()
+	switch {
+	case !prevFd.Flying:
+		return errors.New("not flying")
+	case !prevFd.DownVisualState:
+		return errors.New("no ground visual")
+	}
+	if stickChan == nil { // keyboard only, so nothing has been sending stick messages
+		stickChan, _ = drone.StartStickListener()
+	}
+	holdActive, holdX, holdY = true, prevFd.MVO.PositionX, prevFd.MVO.PositionY
+	fields[fMessage].value = "Holding position - any key or stick to stop"
+	return nil
+}
+
+// cancelPosHold stops holding position, centring the sticks, and reports
+// whether it had been holding
+func cancelPosHold() bool {
+	fieldsMu.Lock()
+	defer fieldsMu.Unlock()
+	if !holdActive {
+		return false
+	}
+	stopPosHold("Position hold off")
+	return true
+}
+
+// stopPosHold ends the hold with a message, fieldsMu must be held
+func stopPosHold(msg string) {
+	holdActive = false
+	stickChan <- tello.StickMessage{}
+	fields[fMessage].value = msg
+}
+
+// posHoldActive reports whether position hold is steering the drone,
+// in which case centred joystick sticks are not sent
+func posHoldActive() bool {
+	fieldsMu.RLock()
+	defer fieldsMu.RUnlock()
+	return holdActive
+}
+
+// holdStick converts an error in metres to a stick value
+func holdStick(err float64) int16 {
+	if math.Abs(err) < holdDeadband {
+		return 0
+	}
+	frac := math.Max(-holdMaxStick, math.Min(holdMaxStick, err*holdGain))
+	return int16(frac * 32767)
+}
+
+// checkPosHold is called from updateFields with fieldsMu held, it sends the
+// stick movements that bring the drone back to the setpoint
+func checkPosHold(fd tello.FlightData) {
+	if !holdActive {
+		return
+	}
+	switch {
+	case !fd.Flying:
+		holdActive = false
+		return
+	case !fd.DownVisualState:
+		stopPosHold("Position hold stopped - ground visual lost")
+		return
+	}
+	// the MVO error, with X forward at yaw 0 and Y to the right, turned into
+	// forward and right for the drone's current heading
+	ex, ey := float64(holdX-fd.MVO.PositionX), float64(holdY-fd.MVO.PositionY)
+	sin, cos := math.Sincos(float64(fd.IMU.Yaw) * math.Pi / 180)
+	fwd := ex*cos + ey*sin
+	rgt := -ex*sin + ey*cos
+	stickChan <- tello.StickMessage{Ry: holdStick(fwd), Rx: holdStick(rgt)}
+}
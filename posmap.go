@@ -0,0 +1,187 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/nsf/termbox-go"
+)
+
+const (
+	trailLen     = 20   // positions kept for the trail
+	trailMinStep = 0.1  // metres moved before a new trail point is recorded
+	mapColScale  = 0.25 // smallest metres per column, rows are twice as tall as columns are wide
+	mapMargin    = 1.1  // room left around the furthest point when scaling
+	mapCornerW   = 24   // size of the map drawn beside the cockpit
+	mapCornerH   = 12
+)
+
+type mapPoint struct{ x, y float32 }
+
+var (
+	trail []mapPoint // recent MVO positions, guarded by fieldsMu
+	// furthest the drone has been from home along each axis, the map is scaled
+	// to keep this in view, guarded by fieldsMu
+	mapExtX, mapExtY float64
+	flyTarget        *mapPoint // where flyTo is heading, relative to the library's home point, guarded by fieldsMu
+)
+
+// setFlyTarget shows x,y (relative to the library's home point, as given to
+// AutoFlyToXY) on the map as the place being flown to, the result is for clearFlyTarget
+func setFlyTarget(x, y float32) *mapPoint {
+	t := &mapPoint{x, y}
+	fieldsMu.Lock()
+	flyTarget = t
+	ox, oy := homeOffset()
+	mapExtX = math.Max(mapExtX, math.Abs(float64(x-ox))) // keep it in view
+	mapExtY = math.Max(mapExtY, math.Abs(float64(y-oy)))
+	fieldsMu.Unlock()
+	return t
+}
+
+// clearFlyTarget removes t from the map, it returns false if a newer target
+// has replaced it
+func clearFlyTarget(t *mapPoint) bool {
+	fieldsMu.Lock()
+	defer fieldsMu.Unlock()
+	if flyTarget != t {
+		return false
+	}
+	flyTarget = nil
+	return true
+}
+
+// homeXY returns the home position, or the origin if home is not set
+func homeXY() (hx, hy float32) {
+	homeMu.Lock()
+	defer homeMu.Unlock()
+	if home != nil {
+		hx, hy = home.X, home.Y
+	}
+	return hx, hy
+}
+
+// homeOffset returns where home is relative to the library's home point
+func homeOffset() (ox, oy float32) {
+	homeMu.Lock()
+	defer homeMu.Unlock()
+	return homeOfX, homeOfY
+}
+
+// stretchMap widens the map bounds if needed so that x,y fits, fieldsMu must be held
+func stretchMap(x, y float32) {
+	hx, hy := homeXY()
+	mapExtX = math.Max(mapExtX, math.Abs(float64(x-hx)))
+	mapExtY = math.Max(mapExtY, math.Abs(float64(y-hy)))
+}
+
+// resetMapScale shrinks the map back to just fit home and the drone
+func resetMapScale() {
+	fieldsMu.Lock()
+	mapExtX, mapExtY = 0, 0
+	stretchMap(prevFd.MVO.PositionX, prevFd.MVO.PositionY)
+	fieldsMu.Unlock()
+}
+
+// recordTrail adds the position to the trail if the drone has moved far enough
+func recordTrail(x, y float32) {
+	stretchMap(x, y)
+	if n := len(trail); n > 0 {
+		dx, dy := float64(x-trail[n-1].x), float64(y-trail[n-1].y)
+		if math.Hypot(dx, dy) < trailMinStep {
+			return
+		}
+	}
+	trail = append(trail, mapPoint{x, y})
+	if len(trail) > trailLen {
+		trail = trail[1:]
+	}
+}
+
+// drawMap renders a top-down view centred on home with the drone as '@',
+// its recent path as dots and any flyto target as 'X', fieldsMu must be read-locked
+func drawMap(x0, y0, w, h int) {
+	hx, hy := homeXY()
+	scale := mapScale(w, h)
+
+	// border and title
+	for x := x0; x < x0+w; x++ {
+		setCell(x, y0, '-', th.Label, th.Background)
+		setCell(x, y0+h-1, '-', th.Label, th.Background)
+	}
+	for y := y0 + 1; y < y0+h-1; y++ {
+		setCell(x0, y, '|', th.Label, th.Background)
+		setCell(x0+w-1, y, '|', th.Label, th.Background)
+		for x := x0 + 1; x < x0+w-1; x++ {
+			setCell(x, y, ' ', th.Value, th.Background)
+		}
+	}
+	tbprint(x0+2, y0, th.Heading, th.Background,
+		fmt.Sprintf(" Map %.2gm/col ", scale))
+
+	// plot converts an MVO position to a cell inside the border, clamping at the edges.
+	// Up is the MVO X axis (yaw 0) and right is the Y axis, matching the compass.
+	cx, cy := x0+w/2, y0+h/2
+	plot := func(px, py float32, ch rune, fg termbox.Attribute) {
+		col := cx + int(math.Round(float64(py-hy)/scale))
+		row := cy - int(math.Round(float64(px-hx)/(scale*2)))
+		col = clampInt(col, x0+1, x0+w-2)
+		row = clampInt(row, y0+1, y0+h-2)
+		setCell(col, row, ch, fg, th.Background)
+	}
+	for _, p := range trail {
+		plot(p.x, p.y, '.', th.Trail)
+	}
+	if flyTarget != nil {
+		ox, oy := homeOffset()
+		plot(hx-ox+flyTarget.x, hy-oy+flyTarget.y, 'X', th.Notice|termbox.AttrBold)
+	}
+	plot(hx, hy, 'H', th.Caution|termbox.AttrBold)
+	plot(prevFd.MVO.PositionX, prevFd.MVO.PositionY, '@', th.Good|termbox.AttrBold)
+}
+
+// mapScale gives the metres per column needed to show the whole flight so far
+// in a map of w by h cells, it is never less than mapColScale
+func mapScale(w, h int) float64 {
+	halfW, halfH := float64((w-2)/2), float64((h-2)/2)
+	scale := mapColScale
+	if halfW > 0 {
+		scale = math.Max(scale, mapExtY*mapMargin/halfW)
+	}
+	if halfH > 0 {
+		scale = math.Max(scale, mapExtX*mapMargin/(2*halfH))
+	}
+	return scale
+}
+
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
@@ -0,0 +1,99 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/nsf/termbox-go"
+)
+
+const landTimeout = 20 * time.Second // give up waiting for touchdown after this
+
+// confirmQuit is called when the user asks to quit, if the drone is still flying
+// it is either landed automatically (-landonquit) or the user is asked what to do.
+// It returns false if the quit was cancelled.
+func confirmQuit() bool {
+	if !currentFd().Flying {
+		return true
+	}
+	if *landOnQuitFlag {
+		landAndWait()
+		return true
+	}
+	showMessage("Tello is still flying!  y: land then quit,  n: quit anyway,  other: cancel")
+	ev := termbox.PollEvent()
+	showMessage("")
+	switch {
+	case ev.Type == termbox.EventInterrupt: // the signal handler has dealt with it
+		return true
+	case ev.Type == termbox.EventKey && ev.Ch == 'y':
+		landAndWait()
+		return true
+	case ev.Type == termbox.EventKey && ev.Ch == 'n':
+		return true
+	}
+	return false
+}
+
+// landAndWait lands the drone and waits for it to report that it is no longer flying
+func landAndWait() {
+	showMessage("Landing...")
+	land()
+	for start := time.Now(); time.Since(start) < landTimeout; {
+		if !currentFd().Flying {
+			break
+		}
+		time.Sleep(updatePeriodMs * time.Millisecond)
+	}
+	showMessage("")
+}
+
+// handleSignals makes SIGINT and SIGTERM quit as tidily as the q key, the drone
+// is landed if -landonquit is set, otherwise it is told to hover
+func handleSignals() {
+	if *videoStdoutFlag {
+		// when whatever is reading the video exits we want a write error, not to die
+		signal.Ignore(syscall.SIGPIPE)
+	}
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigs
+		if currentFd().Flying {
+			if *landOnQuitFlag {
+				landAndWait()
+			} else {
+				drone.Hover()
+			}
+		}
+		if *headlessFlag { // there's no way to interrupt a script, so finish here
+			shutdown()
+			os.Exit(1)
+		}
+		termbox.Interrupt() // makes keyboardLoop return
+	}()
+}
@@ -0,0 +1,92 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/SMerrony/tello"
+)
+
+// The raw log holds one JSON object per line, {"t":"<RFC3339 time>","fd":{...}},
+// where fd is every field of tello.FlightData exactly as the library decoded it.
+// The file is appended to and flushed every second so that a crash loses little.
+
+const rawLogFlushPeriod = time.Second
+
+var (
+	rawLogMu  sync.Mutex
+	rawLogBuf *bufio.Writer
+	rawLogEnc *json.Encoder
+	rawLogF   *os.File
+)
+
+type rawLogRecord struct {
+	T  string           `json:"t"`
+	FD tello.FlightData `json:"fd"`
+}
+
+func startRawLog(path string) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		log.Fatal("Cannot open raw log file: ", err)
+	}
+	rawLogF = f
+	rawLogBuf = bufio.NewWriter(f)
+	rawLogEnc = json.NewEncoder(rawLogBuf)
+	go func() {
+		for range time.Tick(rawLogFlushPeriod) {
+			rawLogMu.Lock()
+			if rawLogBuf == nil {
+				rawLogMu.Unlock()
+				return
+			}
+			rawLogBuf.Flush()
+			rawLogMu.Unlock()
+		}
+	}()
+}
+
+func writeRawLog(now time.Time, fd tello.FlightData) {
+	rawLogMu.Lock()
+	defer rawLogMu.Unlock()
+	if rawLogEnc != nil {
+		rawLogEnc.Encode(rawLogRecord{now.Format(time.RFC3339Nano), fd})
+	}
+}
+
+func stopRawLog() {
+	rawLogMu.Lock()
+	defer rawLogMu.Unlock()
+	if rawLogBuf == nil {
+		return
+	}
+	rawLogBuf.Flush()
+	rawLogF.Close()
+	rawLogBuf, rawLogEnc = nil, nil
+}
@@ -0,0 +1,104 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"errors"
+	"time"
+
+	"github.com/SMerrony/tello"
+)
+
+// readOnlyDrone passes on only the calls that connect, ask for information or
+// receive flight data, everything that could change what the drone is doing
+// is dropped.  It is used for -readonly, e.g. to watch a flight being flown
+// from the phone app.  Every telloDrone method is written out here so that
+// anything added to the interface has to be considered.
+type readOnlyDrone struct {
+	d telloDrone
+}
+
+var errReadOnly = errors.New("not allowed in read only mode")
+
+func (r readOnlyDrone) ControlConnectDefault() error { return r.d.ControlConnectDefault() }
+func (r readOnlyDrone) StreamFlightData(asAvailable bool, periodMs time.Duration) (<-chan tello.FlightData, error) {
+	return r.d.StreamFlightData(asAvailable, periodMs)
+}
+
+// the video is not started as it means sending requests for it
+func (r readOnlyDrone) VideoConnectDefault() (<-chan []byte, error) { return nil, errReadOnly }
+func (r readOnlyDrone) VideoDisconnect()                            {}
+func (r readOnlyDrone) GetVideoSpsPps()                             {}
+func (r readOnlyDrone) SetVideoNormal()                             {}
+func (r readOnlyDrone) SetVideoWide()                               {}
+func (r readOnlyDrone) StartSmartVideo(cmd tello.SvCmd)             {}
+
+func (r readOnlyDrone) GetLowBatteryThreshold()          { r.d.GetLowBatteryThreshold() }
+func (r readOnlyDrone) SetLowBatteryThreshold(thr uint8) {}
+func (r readOnlyDrone) GetMaxHeight()                    { r.d.GetMaxHeight() }
+func (r readOnlyDrone) GetSSID()                         { r.d.GetSSID() }
+func (r readOnlyDrone) GetVersion()                      { r.d.GetVersion() }
+
+func (r readOnlyDrone) TakeOff()      {}
+func (r readOnlyDrone) ThrowTakeOff() {}
+func (r readOnlyDrone) Land()         {}
+func (r readOnlyDrone) PalmLand()     {}
+func (r readOnlyDrone) Bounce()       {}
+func (r readOnlyDrone) Hover()        {}
+func (r readOnlyDrone) SetFastMode()  {}
+func (r readOnlyDrone) SetSlowMode()  {}
+
+func (r readOnlyDrone) Forward(pct int)   {}
+func (r readOnlyDrone) Backward(pct int)  {}
+func (r readOnlyDrone) Left(pct int)      {}
+func (r readOnlyDrone) Right(pct int)     {}
+func (r readOnlyDrone) Up(pct int)        {}
+func (r readOnlyDrone) Down(pct int)      {}
+func (r readOnlyDrone) TurnLeft(pct int)  {}
+func (r readOnlyDrone) TurnRight(pct int) {}
+
+func (r readOnlyDrone) ForwardFlip() {}
+func (r readOnlyDrone) BackFlip()    {}
+func (r readOnlyDrone) LeftFlip()    {}
+func (r readOnlyDrone) RightFlip()   {}
+
+func (r readOnlyDrone) TakePicture() bool                      { return false }
+func (r readOnlyDrone) NumPics() int                           { return 0 }
+func (r readOnlyDrone) SaveAllPics(prefix string) (int, error) { return 0, nil }
+
+// StartStickListener returns a channel whose stick messages are thrown away
+func (r readOnlyDrone) StartStickListener() (chan<- tello.StickMessage, error) {
+	ch := make(chan tello.StickMessage, 10)
+	go func() {
+		for range ch {
+		}
+	}()
+	return ch, nil
+}
+
+func (r readOnlyDrone) SetHome()        {}
+func (r readOnlyDrone) IsHomeSet() bool { return false }
+func (r readOnlyDrone) AutoFlyToXY(x, y float32) (chan bool, error) {
+	return nil, errReadOnly
+}
+func (r readOnlyDrone) CancelAutoFlyToXY() {}
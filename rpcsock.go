@@ -0,0 +1,149 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"sort"
+	"strings"
+)
+
+// The -rpcsock protocol is one JSON object per line in each direction.
+// On connecting the client is greeted with
+//
+//	{"server":"telloterm","methods":["360","backward <pct>",...]}
+//
+// then each request names a command from the script language, with its
+// arguments either by the names in its usage or as a list, e.g.
+//
+//	{"id":1,"method":"flyto","x":1,"y":0}
+//	{"id":2,"method":"flip","args":["b"]}
+//
+// and gets back {"id":1,"result":"ok"} or {"id":1,"error":"..."}.
+// There are two extra methods: "methods" repeats the list and "status"
+// returns the latest telemetry in the -udpout format.
+
+type rpcReply struct {
+	ID     interface{} `json:"id,omitempty"`
+	Result interface{} `json:"result,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+func startRPCSocket(path string) {
+	os.Remove(path) // left over from a previous run
+	lis, err := net.Listen("unix", path)
+	if err != nil {
+		log.Fatalf("Cannot listen on %s - %v", path, err)
+	}
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go serveRPC(conn)
+		}
+	}()
+}
+
+func commandUsages() []string {
+	var usages []string
+	for _, c := range commands {
+		usages = append(usages, c.usage)
+	}
+	sort.Strings(usages)
+	return usages
+}
+
+func serveRPC(conn net.Conn) {
+	defer conn.Close()
+	enc := json.NewEncoder(conn)
+	enc.SetEscapeHTML(false)
+	enc.Encode(map[string]interface{}{"server": "telloterm", "methods": commandUsages()})
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		var req map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			enc.Encode(rpcReply{Error: err.Error()})
+			continue
+		}
+		reply := rpcReply{ID: req["id"]}
+		result, err := rpcCall(req)
+		if err != nil {
+			reply.Error = err.Error()
+		} else {
+			reply.Result = result
+		}
+		enc.Encode(reply)
+	}
+}
+
+func rpcCall(req map[string]interface{}) (interface{}, error) {
+	method, _ := req["method"].(string)
+	switch method {
+	case "methods":
+		return commandUsages(), nil
+	case "status":
+		return newUDPPacket(currentFd()), nil
+	}
+	cmd, ok := commands[strings.ToLower(method)]
+	if !ok {
+		return nil, fmt.Errorf("unknown method <%s>", method)
+	}
+	args, err := rpcArgs(cmd, req)
+	if err != nil {
+		return nil, err
+	}
+	remoteCmdMu.Lock()
+	defer remoteCmdMu.Unlock()
+	if err := runCommand(method + " " + strings.Join(args, " ")); err != nil {
+		return nil, err
+	}
+	return "ok", nil
+}
+
+// rpcArgs gets a command's arguments from an "args" list, or else from
+// members named as in its usage, e.g. "x" and "y" for "flyto <x> <y>"
+func rpcArgs(cmd command, req map[string]interface{}) ([]string, error) {
+	var args []string
+	if list, ok := req["args"].([]interface{}); ok {
+		for _, a := range list {
+			args = append(args, fmt.Sprint(a))
+		}
+		return args, nil
+	}
+	for _, word := range strings.Fields(cmd.usage)[1:] {
+		name := strings.Trim(word, "<>")
+		v, ok := req[name]
+		if !ok {
+			return nil, fmt.Errorf("missing %s, usage: %s", name, cmd.usage)
+		}
+		args = append(args, fmt.Sprint(v))
+	}
+	return args, nil
+}
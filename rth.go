@@ -0,0 +1,95 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/SMerrony/tello"
+)
+
+const rthGrace = 10 * time.Second // time allowed to cancel a low-battery return home
+
+// low-battery return-to-home states
+const (
+	rthIdle = iota
+	rthCountdown
+	rthReturning
+	rthCancelled // not retried until the next flight
+)
+
+var (
+	rthState    int // guarded by fieldsMu
+	rthDeadline time.Time
+)
+
+// checkLowBattRTH is called from updateFields with fieldsMu held, it starts the
+// countdown when the battery falls below -rthbatt and flies home when it expires
+func checkLowBattRTH(fd tello.FlightData, now time.Time) {
+	if !fd.Flying {
+		if rthState != rthIdle {
+			fields[fMessage].value = ""
+		}
+		rthState = rthIdle
+		return
+	}
+	switch rthState {
+	case rthIdle:
+		if int(fd.BatteryPercentage) <= *rthBattFlag && drone.IsHomeSet() && fd.DownVisualState {
+			rthState = rthCountdown
+			rthDeadline = now.Add(rthGrace)
+		}
+	case rthCountdown:
+		left := rthDeadline.Sub(now)
+		if left > 0 {
+			fields[fMessage].value = fmt.Sprintf("LOW BATTERY - returning home in %.0fs, press c to cancel", left.Seconds())
+			return
+		}
+		if !fd.DownVisualState {
+			fields[fMessage].value = "LOW BATTERY - cannot return home, ground tracking lost"
+			rthState = rthCancelled
+			return
+		}
+		fields[fMessage].value = "LOW BATTERY - returning home"
+		rthState = rthReturning
+		go goHome()
+	}
+}
+
+// cancelLowBattRTH stops a pending or active low-battery return home
+func cancelLowBattRTH() {
+	fieldsMu.Lock()
+	defer fieldsMu.Unlock()
+	switch rthState {
+	case rthCountdown:
+		fields[fMessage].value = "Return home cancelled"
+	case rthReturning:
+		drone.CancelAutoFlyToXY()
+		drone.Hover()
+		fields[fMessage].value = "Return home cancelled"
+	default:
+		return
+	}
+	rthState = rthCancelled
+}
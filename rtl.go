@@ -0,0 +1,112 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/SMerrony/tello"
+)
+
+// Return and land flies home and lands once the drone has arrived and stopped.
+
+const (
+	rtlArriveDist = 0.3             // metres from home that counts as arrived
+	rtlSettleTime = time.Second     // how long it must then stay still before landing
+	rtlTimeout    = 2 * time.Minute // give up if home is not reached by then
+)
+
+var (
+	rtlActive  bool // guarded by fieldsMu
+	rtlStarted time.Time
+	rtlStill   time.Time // when the drone was first seen stopped at home, zero if not
+)
+
+// startReturnAndLand begins flying home, landing is left to checkReturnAndLand
+func startReturnAndLand() error {
+	if !currentFd().Flying {
+		return errors.New("not flying")
+	}
+	if err := goHome(); err != nil {
+		return err
+	}
+	fieldsMu.Lock()
+	rtlActive, rtlStarted, rtlStill = true, time.Now(), time.Time{}
+	fields[fMessage].value = "Returning home to land, press c to cancel"
+	fieldsMu.Unlock()
+	return nil
+}
+
+// cancelReturnAndLand stops a return and land, leaving the drone hovering
+func cancelReturnAndLand() {
+	fieldsMu.Lock()
+	defer fieldsMu.Unlock()
+	if !rtlActive {
+		return
+	}
+	rtlActive = false
+	drone.CancelAutoFlyToXY()
+	drone.Hover()
+	fields[fMessage].value = "Return and land cancelled"
+}
+
+// checkReturnAndLand is called from updateFields with fieldsMu held, it lands
+// once the drone has been close to home and still for rtlSettleTime
+func checkReturnAndLand(fd tello.FlightData, now time.Time) {
+	if !rtlActive {
+		return
+	}
+	if !fd.Flying {
+		rtlActive = false
+		return
+	}
+	if now.Sub(rtlStarted) > rtlTimeout {
+		rtlActive = false
+		drone.Hover()
+		fields[fMessage].value = "Return and land gave up - home not reached"
+		return
+	}
+	homeMu.Lock()
+	if home == nil {
+		homeMu.Unlock()
+		return
+	}
+	// goHome aims at home itself, homeOfX/Y only say where that is from the library's home point
+	dist := math.Hypot(float64(fd.MVO.PositionX-home.X), float64(fd.MVO.PositionY-home.Y))
+	homeMu.Unlock()
+	if dist > rtlArriveDist || fd.NorthSpeed != 0 || fd.EastSpeed != 0 {
+		rtlStill = time.Time{}
+		return
+	}
+	if rtlStill.IsZero() {
+		rtlStill = now
+		return
+	}
+	if now.Sub(rtlStill) >= rtlSettleTime {
+		rtlActive = false
+		fields[fMessage].value = "Home - landing"
+		go land() // we hold fieldsMu here
+	}
+}
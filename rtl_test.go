@@ -0,0 +1,67 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SMerrony/tello"
+)
+
+// TestReturnAndLandRestoredHome checks that arrival is judged against home
+// itself when an old home has been restored, i.e. homeOfX/Y are non-zero
+func TestReturnAndLandRestoredHome(t *testing.T) {
+	savedHome, savedOfX, savedOfY := home, homeOfX, homeOfY
+	defer func() {
+		home, homeOfX, homeOfY = savedHome, savedOfX, savedOfY
+		rtlActive = false
+	}()
+	drone = newSimDrone() // left in place as landing calls it from a goroutine
+	home, homeOfX, homeOfY = &homePos{X: 2, Y: 1}, 3, -1.5
+
+	tests := []struct {
+		name   string
+		x, y   float32
+		landed bool
+	}{
+		{"at home", 2, 1, true},
+		{"at home plus offset", 5, -0.5, false},
+		{"short of home", 1, 1, false},
+	}
+	for _, tt := range tests {
+		var fd tello.FlightData
+		fd.Flying = true
+		fd.MVO.PositionX, fd.MVO.PositionY = tt.x, tt.y
+		start := time.Now()
+		fieldsMu.Lock()
+		rtlActive, rtlStarted, rtlStill = true, start, time.Time{}
+		checkReturnAndLand(fd, start)
+		checkReturnAndLand(fd, start.Add(rtlSettleTime))
+		landed := !rtlActive
+		fieldsMu.Unlock()
+		if landed != tt.landed {
+			t.Errorf("%s: landed %v, want %v", tt.name, landed, tt.landed)
+		}
+	}
+}
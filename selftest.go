@@ -0,0 +1,132 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"log"
+	"math"
+	"time"
+
+	"github.com/SMerrony/tello"
+	"github.com/nsf/termbox-go"
+)
+
+// The self-test feeds made-up flight data through updateFields and draws it,
+// sweeping the numbers over their ranges and flipping the flags, so that the
+// layout and colours can be checked on a terminal without a drone.
+
+const (
+	selfTestSteps  = 200
+	selfTestPeriod = 50 * time.Millisecond
+)
+
+// selfTestFd gives the fake flight data for step i of n
+func selfTestFd(i, n int) tello.FlightData {
+	frac := float64(i) / float64(n-1) // 0 to 1 over the test
+	ang := frac * 2 * math.Pi
+	phase := i * 8 / n // eight phases for the flags
+	var fd tello.FlightData
+
+	fd.Height = int16(100 * math.Sin(ang/2) * 10)
+	fd.BatteryPercentage = int8(100 - 100*frac)
+	fd.BatteryMilliVolts = int16(4350 - 1000*frac)
+	fd.BatteryLow = fd.BatteryPercentage < 20
+	fd.BatteryCritical = fd.BatteryPercentage < 10
+	fd.BatteryState = phase%2 == 1
+	fd.WifiStrength = uint8(90 - 80*frac)
+	fd.WifiInterference = uint8(100 * frac)
+	fd.MaxHeight = 30
+	fd.LowBatteryThreshold = 20
+	fd.LightStrength = uint8(phase % 2)
+	fd.DownVisualState = phase%2 == 0
+	fd.ErrorState = phase == 7
+
+	fd.NorthSpeed = int16(math.Round(8 * math.Cos(ang)))
+	fd.EastSpeed = int16(math.Round(8 * math.Sin(ang)))
+	fd.GroundSpeed = int16(math.Round(math.Hypot(float64(fd.NorthSpeed), float64(fd.EastSpeed))))
+	fd.VerticalSpeed = int16(math.Round(3 * math.Cos(ang/2)))
+
+	fd.Flying = phase >= 1 && phase <= 6
+	fd.OnGround = !fd.Flying
+	fd.DroneHover = phase == 3 || phase == 4
+	fd.FlyMode = uint8(phase)
+	fd.CameraState = uint8(phase % 2)
+	fd.DroneFlyTimeLeft = int16(600 - 600*frac)
+
+	fd.MVO.PositionX = float32(3 * math.Sin(ang))
+	fd.MVO.PositionY = float32(3 * math.Sin(2*ang))
+	fd.MVO.PositionZ = -float32(fd.Height) / 10
+	fd.MVO.VelocityX = fd.NorthSpeed * 100
+	fd.MVO.VelocityY = fd.EastSpeed * 100
+	fd.MVO.VelocityZ = fd.VerticalSpeed * 100
+
+	fd.IMU.Yaw = int16(math.Round(360*frac)) - 180
+	fd.IMU.Temperature = int16(40 + 60*frac)
+	fd.IMU.QuaternionW = float32(math.Cos(ang / 2))
+	fd.IMU.QuaternionZ = float32(math.Sin(ang / 2))
+
+	fd.SSID = "TELLO-SELFTEST"
+	fd.Version = "0.0.0.0"
+	return fd
+}
+
+// runSelfTest shows one full cycle of fake data, stopping early on any key
+func runSelfTest() {
+	*ttsFlag, *rthBattFlag, *battIDFlag = false, 0, "" // nothing made up may be spoken, acted on or recorded
+	setupFields()
+	if *fieldsFlag != "" {
+		packFields(*fieldsFlag)
+	}
+	if err := termbox.Init(); err != nil {
+		log.Fatalf("Cannot start the display - %v", err)
+	}
+	defer termbox.Close()
+	checkTermSize()
+	displayStaticFields()
+
+	keys := make(chan struct{})
+	go func() {
+		for {
+			if ev := termbox.PollEvent(); ev.Type == termbox.EventKey || ev.Type == termbox.EventInterrupt {
+				close(keys)
+				return
+			}
+		}
+	}()
+	showMessage("Self-test - press any key to stop")
+	tick := time.NewTicker(selfTestPeriod)
+	defer tick.Stop()
+	for i := 0; i < selfTestSteps; i++ {
+		fieldsMu.Lock()
+		updateFields(selfTestFd(i, selfTestSteps))
+		fieldsMu.Unlock()
+		if !termTooSmall() {
+			displayDataFields()
+		}
+		select {
+		case <-keys:
+			return
+		case <-tick.C:
+		}
+	}
+}
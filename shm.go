@@ -0,0 +1,140 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"encoding/binary"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/SMerrony/tello"
+)
+
+// The -shm file holds the latest flight data at fixed offsets so that other local
+// processes can mmap it and read it without any IPC.  All values are little-endian.
+//
+//	offset type     contents
+//	  0    [4]byte  magic "TTFD"
+//	  4    uint32   layout version, currently 1
+//	  8    uint32   sequence, odd while an update is being written
+//	 12    uint32   reserved
+//	 16    int64    time of the update, Unix nanoseconds
+//	 24    float32  MVO position X, Y, Z (3 values)
+//	 36    int16    MVO velocity X, Y, Z (3 values)
+//	 42    int16    north, east and vertical speed (3 values)
+//	 48    int16    height in decimetres
+//	 50    int16    yaw, pitch, roll in degrees (3 values)
+//	 56    float32  IMU quaternion W, X, Y, Z (4 values)
+//	 72    int16    battery millivolts
+//	 74    int8     battery percentage
+//	 75    uint8    WiFi strength
+//	 76    uint8    WiFi interference
+//	 77    uint8    flags, bit 0 flying, 1 on ground, 2 hovering, 3 battery low,
+//	                4 battery critical, 5 ground visual
+//	 78    int16    IMU temperature
+//	 80-127         reserved, zero
+//
+// A reader should read the sequence, then the data, then the sequence again and
+// retry if the two differ or are odd.  New fields only ever go in the reserved
+// space, anything else changes the version.
+
+const (
+	shmSize    = 128
+	shmVersion = 1
+)
+
+var (
+	shmMu  sync.Mutex
+	shmMem []byte // nil unless -shm was given
+)
+
+// shmFlags packs the boolean state into the flags byte
+func shmFlags(fd tello.FlightData) (f uint8) {
+	for bit, set := range []bool{fd.Flying, fd.OnGround, fd.DroneHover, fd.BatteryLow, fd.BatteryCritical, fd.DownVisualState} {
+		if set {
+			f |= 1 << uint(bit)
+		}
+	}
+	return f
+}
+
+// encodeShm fills b, which must be shmSize long, with everything but the header
+func encodeShm(b []byte, fd tello.FlightData, now time.Time) {
+	le := binary.LittleEndian
+	pitch, roll, _ := tello.QuatToEulerDeg(fd.IMU.QuaternionX, fd.IMU.QuaternionY, fd.IMU.QuaternionZ, fd.IMU.QuaternionW)
+	le.PutUint64(b[16:], uint64(now.UnixNano()))
+	for i, v := range []float32{fd.MVO.PositionX, fd.MVO.PositionY, fd.MVO.PositionZ} {
+		le.PutUint32(b[24+4*i:], math.Float32bits(v))
+	}
+	for i, v := range []int16{fd.MVO.VelocityX, fd.MVO.VelocityY, fd.MVO.VelocityZ,
+		fd.NorthSpeed, fd.EastSpeed, fd.VerticalSpeed, fd.Height,
+		fd.IMU.Yaw, int16(pitch), int16(roll)} {
+		le.PutUint16(b[36+2*i:], uint16(v))
+	}
+	for i, v := range []float32{fd.IMU.QuaternionW, fd.IMU.QuaternionX, fd.IMU.QuaternionY, fd.IMU.QuaternionZ} {
+		le.PutUint32(b[56+4*i:], math.Float32bits(v))
+	}
+	le.PutUint16(b[72:], uint16(fd.BatteryMilliVolts))
+	b[74] = uint8(fd.BatteryPercentage)
+	b[75] = fd.WifiStrength
+	b[76] = fd.WifiInterference
+	b[77] = shmFlags(fd)
+	le.PutUint16(b[78:], uint16(fd.IMU.Temperature))
+}
+
+func startShm(path string) error {
+	mem, err := mapShm(path, shmSize)
+	if err != nil {
+		return err
+	}
+	for i := range mem {
+		mem[i] = 0
+	}
+	copy(mem, "TTFD")
+	binary.LittleEndian.PutUint32(mem[4:], shmVersion)
+	shmMem = mem
+	return nil
+}
+
+// writeShm publishes fd, bracketed by sequence updates
+func writeShm(fd tello.FlightData) {
+	shmMu.Lock()
+	defer shmMu.Unlock()
+	if shmMem == nil {
+		return
+	}
+	seq := shmSeq(shmMem)
+	setShmSeq(shmMem, seq+1) // odd - update in progress
+	encodeShm(shmMem, fd, time.Now())
+	setShmSeq(shmMem, seq+2)
+}
+
+func stopShm() {
+	shmMu.Lock()
+	defer shmMu.Unlock()
+	if shmMem != nil {
+		unmapShm(shmMem)
+		shmMem = nil
+	}
+}
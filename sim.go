@@ -0,0 +1,305 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/SMerrony/tello"
+)
+
+// rough performance figures for the simulated drone
+const (
+	simSlowSpeed   = 1.0      // m/s at full stick in slow mode
+	simFastSpeed   = 2.5      // m/s at full stick in fast mode
+	simClimbRate   = 1.0      // m/s at full stick
+	simTurnRate    = 90.0     // degrees/s at full stick
+	simTakeoffHt   = 1.2      // metres
+	simFlyDrain    = 0.1      // battery % per second while flying
+	simIdleDrain   = 1.0 / 60 // battery % per second on the ground
+	simAutoSpeed   = 0.5      // m/s when flying to a point
+	simArriveDist  = 0.1      // metres
+	simStickScale  = 1.0 / 32767
+	simCmdMaxValue = 100.0 // the pct argument of Forward() etc.
+)
+
+var errSimNoVideo = errors.New("there is no video in the simulator")
+
+// simDrone is a stand-in for a Tello which answers commands by updating a
+// crude flight model, the model position uses the same axes as the MVO data
+type simDrone struct {
+	mu       sync.Mutex
+	fd       tello.FlightData
+	x, y, z  float64 // metres, z is height
+	yaw      float64 // degrees clockwise from the X axis
+	battery  float64 // percent
+	fast     bool
+	fwd, rgt float64 // -1..1 from keyboard commands or the sticks
+	climb    float64
+	turn     float64
+	homeSet  bool
+	homeX    float64
+	homeY    float64
+	target   *[2]float64 // set while flying to a point
+	arrived  chan bool
+	pics     int
+}
+
+func newSimDrone() *simDrone {
+	return &simDrone{
+		battery: 100,
+		fd: tello.FlightData{
+			SSID:                "TELLO-SIM",
+			Version:             "sim",
+			LowBatteryThreshold: 20,
+			MaxHeight:           30,
+			WifiStrength:        90,
+			LightStrength:       1,
+			DownVisualState:     true,
+			OnGround:            true,
+			IMU:                 tello.IMUData{QuaternionW: 1, Temperature: 45},
+		},
+	}
+}
+
+func (s *simDrone) ControlConnectDefault() error { return nil }
+
+// StreamFlightData steps the model every periodMs milliseconds and sends the result
+func (s *simDrone) StreamFlightData(asAvailable bool, periodMs time.Duration) (<-chan tello.FlightData, error) {
+	ch := make(chan tello.FlightData, 1)
+	go func() {
+		period := periodMs * time.Millisecond
+		for range time.Tick(period) {
+			ch <- s.step(period.Seconds())
+		}
+	}()
+	return ch, nil
+}
+
+// step advances the model by dt seconds
+func (s *simDrone) step(dt float64) tello.FlightData {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fwd, rgt := s.fwd, s.rgt
+	speed := simSlowSpeed
+	if s.fast {
+		speed = simFastSpeed
+	}
+	if s.target != nil && s.fd.Flying {
+		dx, dy := s.homeX+s.target[0]-s.x, s.homeY+s.target[1]-s.y
+		if dist := math.Hypot(dx, dy); dist < simArriveDist {
+			s.target = nil
+			s.arrived <- true
+		} else {
+			s.yaw = math.Atan2(dy, dx) * 180 / math.Pi
+			fwd, rgt, speed = 1, 0, math.Min(simAutoSpeed, dist/dt)
+		}
+	}
+	var vx, vy, vz float64
+	if s.fd.Flying {
+		s.yaw = math.Mod(s.yaw+s.turn*simTurnRate*dt+540, 360) - 180
+		sin, cos := math.Sincos(s.yaw * math.Pi / 180)
+		vx = (fwd*cos - rgt*sin) * speed
+		vy = (fwd*sin + rgt*cos) * speed
+		vz = s.climb * simClimbRate
+		if s.z+vz*dt > float64(s.fd.MaxHeight) {
+			vz = 0
+		}
+		s.x += vx * dt
+		s.y += vy * dt
+		s.z = math.Max(0, s.z+vz*dt)
+		s.battery -= simFlyDrain * dt
+	} else {
+		s.battery -= simIdleDrain * dt
+	}
+	s.battery = math.Max(0, s.battery)
+
+	fd := &s.fd
+	fd.Height = int16(math.Round(s.z * 10))
+	fd.BatteryPercentage = int8(math.Ceil(s.battery))
+	fd.BatteryLow = fd.BatteryPercentage <= int8(fd.LowBatteryThreshold)
+	fd.BatteryCritical = fd.BatteryPercentage <= 10
+	fd.DroneFlyTimeLeft = int16(s.battery / simFlyDrain / 10)
+	fd.OnGround = !fd.Flying
+	fd.DroneHover = fd.Flying && vx == 0 && vy == 0 && vz == 0 && s.turn == 0
+	fd.MVO.PositionX, fd.MVO.PositionY, fd.MVO.PositionZ = float32(s.x), float32(s.y), float32(0-s.z)
+	fd.MVO.VelocityX, fd.MVO.VelocityY, fd.MVO.VelocityZ = int16(vx*100), int16(vy*100), int16(-vz*100)
+	fd.NorthSpeed, fd.EastSpeed, fd.VerticalSpeed = int16(vx), int16(vy), int16(vz)
+	fd.GroundSpeed = int16(math.Hypot(vx, vy))
+	fd.IMU.Yaw = int16(math.Round(s.yaw))
+	half := s.yaw * math.Pi / 360
+	fd.IMU.QuaternionW, fd.IMU.QuaternionZ = float32(math.Cos(half)), float32(math.Sin(half))
+	return *fd
+}
+
+func (s *simDrone) VideoConnectDefault() (<-chan []byte, error) { return nil, errSimNoVideo }
+func (s *simDrone) VideoDisconnect()                            {}
+func (s *simDrone) GetVideoSpsPps()                             {}
+func (s *simDrone) SetVideoNormal()                             {}
+func (s *simDrone) SetVideoWide()                               {}
+func (s *simDrone) StartSmartVideo(cmd tello.SvCmd)             {}
+
+// the simulated values are already in the flight data
+func (s *simDrone) GetLowBatteryThreshold() {}
+func (s *simDrone) GetMaxHeight()           {}
+func (s *simDrone) GetSSID()                {}
+func (s *simDrone) GetVersion()             {}
+
+func (s *simDrone) SetLowBatteryThreshold(thr uint8) {
+	s.mu.Lock()
+	s.fd.LowBatteryThreshold = thr
+	s.mu.Unlock()
+}
+
+func (s *simDrone) TakeOff() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.fd.Flying {
+		s.fd.Flying = true
+		s.z = simTakeoffHt
+	}
+}
+
+func (s *simDrone) ThrowTakeOff() { s.TakeOff() }
+
+func (s *simDrone) Land() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fd.Flying = false
+	s.z = 0
+	s.fwd, s.rgt, s.climb, s.turn = 0, 0, 0, 0
+	s.cancelTarget()
+}
+
+func (s *simDrone) PalmLand() { s.Land() }
+func (s *simDrone) Bounce()   {}
+
+func (s *simDrone) Hover() {
+	s.mu.Lock()
+	s.fwd, s.rgt, s.climb, s.turn = 0, 0, 0, 0
+	s.mu.Unlock()
+}
+
+func (s *simDrone) SetFastMode() { s.mu.Lock(); s.fast = true; s.mu.Unlock() }
+func (s *simDrone) SetSlowMode() { s.mu.Lock(); s.fast = false; s.mu.Unlock() }
+
+// set changes one of the control inputs, as the keyboard commands do
+func (s *simDrone) set(ctl *float64, pct int) {
+	s.mu.Lock()
+	*ctl = float64(pct) / simCmdMaxValue
+	s.mu.Unlock()
+}
+
+func (s *simDrone) Forward(pct int)   { s.set(&s.fwd, pct) }
+func (s *simDrone) Backward(pct int)  { s.set(&s.fwd, -pct) }
+func (s *simDrone) Left(pct int)      { s.set(&s.rgt, -pct) }
+func (s *simDrone) Right(pct int)     { s.set(&s.rgt, pct) }
+func (s *simDrone) Up(pct int)        { s.set(&s.climb, pct) }
+func (s *simDrone) Down(pct int)      { s.set(&s.climb, -pct) }
+func (s *simDrone) TurnLeft(pct int)  { s.set(&s.turn, -pct) }
+func (s *simDrone) TurnRight(pct int) { s.set(&s.turn, pct) }
+
+func (s *simDrone) ForwardFlip() {}
+func (s *simDrone) BackFlip()    {}
+func (s *simDrone) LeftFlip()    {}
+func (s *simDrone) RightFlip()   {}
+
+func (s *simDrone) TakePicture() bool {
+	s.mu.Lock()
+	s.pics++
+	s.mu.Unlock()
+	return true
+}
+
+func (s *simDrone) NumPics() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pics
+}
+
+// SaveAllPics discards the pretend pictures, there is nothing to save
+func (s *simDrone) SaveAllPics(prefix string) (int, error) {
+	s.mu.Lock()
+	s.pics = 0
+	s.mu.Unlock()
+	return 0, nil
+}
+
+// StartStickListener sets the control inputs from the stick messages
+func (s *simDrone) StartStickListener() (chan<- tello.StickMessage, error) {
+	ch := make(chan tello.StickMessage, 10)
+	go func() {
+		for sm := range ch {
+			s.mu.Lock()
+			s.rgt = float64(sm.Rx) * simStickScale
+			s.fwd = float64(sm.Ry) * simStickScale
+			s.turn = float64(sm.Lx) * simStickScale
+			s.climb = float64(sm.Ly) * simStickScale
+			s.mu.Unlock()
+		}
+	}()
+	return ch, nil
+}
+
+func (s *simDrone) SetHome() {
+	s.mu.Lock()
+	s.homeSet, s.homeX, s.homeY = true, s.x, s.y
+	s.mu.Unlock()
+}
+
+func (s *simDrone) IsHomeSet() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.homeSet
+}
+
+// AutoFlyToXY flies straight to x,y metres from home, done gets true on arrival
+func (s *simDrone) AutoFlyToXY(x, y float32) (chan bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.homeSet {
+		return nil, errors.New("home is not set")
+	}
+	s.cancelTarget()
+	s.target = &[2]float64{float64(x), float64(y)}
+	s.arrived = make(chan bool, 1)
+	return s.arrived, nil
+}
+
+func (s *simDrone) CancelAutoFlyToXY() {
+	s.mu.Lock()
+	s.cancelTarget()
+	s.mu.Unlock()
+}
+
+// cancelTarget abandons any flight to a point, s.mu must be held
+func (s *simDrone) cancelTarget() {
+	if s.target != nil {
+		s.target = nil
+		s.arrived <- false
+	}
+}
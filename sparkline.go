@@ -0,0 +1,93 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"time"
+
+	"github.com/nsf/termbox-go"
+)
+
+// A small trend of battery percentage beside the Battery field, each column
+// coloured as the field itself would have been at the time.
+
+const (
+	sparkX      = 48
+	sparkY      = 2
+	sparkW      = 12
+	sparkPeriod = 15 * time.Second // so the trend covers the last 3 minutes
+)
+
+var sparkBlocks = []rune{'▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+
+type sparkSample struct {
+	pct int8
+	fg  termbox.Attribute
+}
+
+var (
+	sparkSamples []sparkSample
+	lastSpark    time.Time
+)
+
+// battColour is green, yellow or red depending on how close pct is to the
+// drone's low battery threshold
+func battColour(pct int8, lowThresh uint8) termbox.Attribute {
+	switch {
+	case int(pct) <= int(lowThresh):
+		return th.Bad
+	case int(pct) <= 2*int(lowThresh):
+		return th.Caution
+	default:
+		return th.Good
+	}
+}
+
+// addSparkSample records the battery level for the trend every sparkPeriod,
+// fieldsMu must be held
+func addSparkSample(now time.Time, pct int8, fg termbox.Attribute) {
+	if now.Sub(lastSpark) < sparkPeriod {
+		return
+	}
+	lastSpark = now
+	sparkSamples = append(sparkSamples, sparkSample{pct, fg})
+	if len(sparkSamples) > sparkW {
+		sparkSamples = sparkSamples[len(sparkSamples)-sparkW:]
+	}
+}
+
+// drawBattSpark draws the battery trend with the newest sample on the right,
+// fieldsMu must be read-locked
+func drawBattSpark() {
+	start := sparkX + sparkW - len(sparkSamples)
+	for x := sparkX; x < start; x++ {
+		setCell(x, sparkY, ' ', th.Value, th.Background)
+	}
+	for i, s := range sparkSamples {
+		level := int(s.pct) * len(sparkBlocks) / 101
+		if level < 0 {
+			level = 0
+		}
+		setCell(start+i, sparkY, sparkBlocks[level], s.fg, th.Background)
+	}
+}
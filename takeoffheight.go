@@ -0,0 +1,115 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/SMerrony/tello"
+)
+
+// With -takeoffheight the drone climbs or descends to a set height once its
+// own takeoff has finished, then hovers.  Any key or stick movement stops it.
+
+const (
+	tohTolerance = 0.1              // metres either side of the target that will do
+	tohClimbPct  = 40               // Up/Down speed used to get there
+	tohTimeout   = 20 * time.Second // give up if the height is not reached by then
+)
+
+var (
+	tohArmed   bool // a takeoff has been commanded, guarded by fieldsMu
+	tohActive  bool // climbing or descending to the target
+	tohStarted time.Time
+	tohDir     int // +1 climbing, -1 descending
+)
+
+// armTakeOffHeight is called when a takeoff is commanded
+func armTakeOffHeight() {
+	if *takeOffHtFlag <= 0 {
+		return
+	}
+	fieldsMu.Lock()
+	tohArmed, tohActive = true, false
+	fieldsMu.Unlock()
+}
+
+// cancelTakeOffHeight stops any move to the takeoff height, called on manual input
+func cancelTakeOffHeight() {
+	fieldsMu.Lock()
+	defer fieldsMu.Unlock()
+	active := tohActive
+	tohArmed, tohActive = false, false
+	if active {
+		fields[fMessage].value = "Takeoff height cancelled"
+	}
+}
+
+// takeOffHeightActive reports whether the drone is being moved to the takeoff height,
+// in which case centred sticks are not sent so as not to stop it
+func takeOffHeightActive() bool {
+	fieldsMu.RLock()
+	defer fieldsMu.RUnlock()
+	return tohActive
+}
+
+// checkTakeOffHeight is called from updateFields with fieldsMu held, it waits
+// for the drone's own takeoff to finish and then steers it to the target height
+func checkTakeOffHeight(fd tello.FlightData, state string, now time.Time) {
+	if !tohArmed && !tohActive {
+		return
+	}
+	if !fd.Flying && !tohArmed {
+		tohActive = false
+		return
+	}
+	if tohArmed {
+		if !fd.Flying || state == "TAKING OFF" {
+			return
+		}
+		tohArmed, tohActive, tohStarted, tohDir = false, true, now, 0
+	}
+	if now.Sub(tohStarted) > tohTimeout {
+		tohActive = false
+		drone.Hover()
+		fields[fMessage].value = "Takeoff height not reached - hovering"
+		return
+	}
+	diff := *takeOffHtFlag - float64(fd.Height)/10
+	if math.Abs(diff) <= tohTolerance {
+		tohActive = false
+		drone.Hover()
+		fields[fMessage].value = fmt.Sprintf("At takeoff height %.1fm", *takeOffHtFlag)
+		return
+	}
+	switch {
+	case diff > 0 && tohDir != 1:
+		tohDir = 1
+		drone.Up(tohClimbPct)
+	case diff < 0 && tohDir != -1:
+		tohDir = -1
+		drone.Down(tohClimbPct)
+	}
+}
@@ -0,0 +1,740 @@
+// Control and telemetry service offered by telloterm when started with -grpc.
+//
+// Regenerate the Go stubs with
+//   go generate -tags grpc
+// which needs protoc, protoc-gen-go and protoc-gen-go-grpc on your PATH.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: tellopb/telloterm.proto
+
+package tellopb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type FlipDirection int32
+
+const (
+	FlipDirection_FLIP_FORWARD FlipDirection = 0
+	FlipDirection_FLIP_BACK    FlipDirection = 1
+	FlipDirection_FLIP_LEFT    FlipDirection = 2
+	FlipDirection_FLIP_RIGHT   FlipDirection = 3
+)
+
+// Enum value maps for FlipDirection.
+var (
+	FlipDirection_name = map[int32]string{
+		0: "FLIP_FORWARD",
+		1: "FLIP_BACK",
+		2: "FLIP_LEFT",
+		3: "FLIP_RIGHT",
+	}
+	FlipDirection_value = map[string]int32{
+		"FLIP_FORWARD": 0,
+		"FLIP_BACK":    1,
+		"FLIP_LEFT":    2,
+		"FLIP_RIGHT":   3,
+	}
+)
+
+func (x FlipDirection) Enum() *FlipDirection {
+	p := new(FlipDirection)
+	*p = x
+	return p
+}
+
+func (x FlipDirection) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FlipDirection) Descriptor() protoreflect.EnumDescriptor {
+	return file_tellopb_telloterm_proto_enumTypes[0].Descriptor()
+}
+
+func (FlipDirection) Type() protoreflect.EnumType {
+	return &file_tellopb_telloterm_proto_enumTypes[0]
+}
+
+func (x FlipDirection) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FlipDirection.Descriptor instead.
+func (FlipDirection) EnumDescriptor() ([]byte, []int) {
+	return file_tellopb_telloterm_proto_rawDescGZIP(), []int{0}
+}
+
+type Empty struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *Empty) Reset() {
+	*x = Empty{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tellopb_telloterm_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Empty) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Empty) ProtoMessage() {}
+
+func (x *Empty) ProtoReflect() protoreflect.Message {
+	mi := &file_tellopb_telloterm_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Empty.ProtoReflect.Descriptor instead.
+func (*Empty) Descriptor() ([]byte, []int) {
+	return file_tellopb_telloterm_proto_rawDescGZIP(), []int{0}
+}
+
+type Result struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ok    bool   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	Error string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"` // set when ok is false
+}
+
+func (x *Result) Reset() {
+	*x = Result{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tellopb_telloterm_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Result) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Result) ProtoMessage() {}
+
+func (x *Result) ProtoReflect() protoreflect.Message {
+	mi := &file_tellopb_telloterm_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Result.ProtoReflect.Descriptor instead.
+func (*Result) Descriptor() ([]byte, []int) {
+	return file_tellopb_telloterm_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Result) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *Result) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type FlipRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Direction FlipDirection `protobuf:"varint,1,opt,name=direction,proto3,enum=telloterm.FlipDirection" json:"direction,omitempty"`
+}
+
+func (x *FlipRequest) Reset() {
+	*x = FlipRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tellopb_telloterm_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FlipRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FlipRequest) ProtoMessage() {}
+
+func (x *FlipRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tellopb_telloterm_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FlipRequest.ProtoReflect.Descriptor instead.
+func (*FlipRequest) Descriptor() ([]byte, []int) {
+	return file_tellopb_telloterm_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *FlipRequest) GetDirection() FlipDirection {
+	if x != nil {
+		return x.Direction
+	}
+	return FlipDirection_FLIP_FORWARD
+}
+
+// FlyToRequest gives a target position in the MVO frame relative to home
+type FlyToRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	X float32 `protobuf:"fixed32,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y float32 `protobuf:"fixed32,2,opt,name=y,proto3" json:"y,omitempty"`
+}
+
+func (x *FlyToRequest) Reset() {
+	*x = FlyToRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tellopb_telloterm_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FlyToRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FlyToRequest) ProtoMessage() {}
+
+func (x *FlyToRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tellopb_telloterm_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FlyToRequest.ProtoReflect.Descriptor instead.
+func (*FlyToRequest) Descriptor() ([]byte, []int) {
+	return file_tellopb_telloterm_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *FlyToRequest) GetX() float32 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *FlyToRequest) GetY() float32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+type StreamRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PeriodMs uint32 `protobuf:"varint,1,opt,name=period_ms,json=periodMs,proto3" json:"period_ms,omitempty"` // 0 means the telloterm update period (50ms)
+}
+
+func (x *StreamRequest) Reset() {
+	*x = StreamRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tellopb_telloterm_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamRequest) ProtoMessage() {}
+
+func (x *StreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tellopb_telloterm_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamRequest.ProtoReflect.Descriptor instead.
+func (*StreamRequest) Descriptor() ([]byte, []int) {
+	return file_tellopb_telloterm_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *StreamRequest) GetPeriodMs() uint32 {
+	if x != nil {
+		return x.PeriodMs
+	}
+	return 0
+}
+
+type FlightData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TimeUnixMs        int64   `protobuf:"varint,1,opt,name=time_unix_ms,json=timeUnixMs,proto3" json:"time_unix_ms,omitempty"`
+	HeightM           float32 `protobuf:"fixed32,2,opt,name=height_m,json=heightM,proto3" json:"height_m,omitempty"`
+	BatteryPercent    int32   `protobuf:"varint,3,opt,name=battery_percent,json=batteryPercent,proto3" json:"battery_percent,omitempty"`
+	BatteryMillivolts int32   `protobuf:"varint,4,opt,name=battery_millivolts,json=batteryMillivolts,proto3" json:"battery_millivolts,omitempty"`
+	WifiStrength      int32   `protobuf:"varint,5,opt,name=wifi_strength,json=wifiStrength,proto3" json:"wifi_strength,omitempty"`
+	Flying            bool    `protobuf:"varint,6,opt,name=flying,proto3" json:"flying,omitempty"`
+	OnGround          bool    `protobuf:"varint,7,opt,name=on_ground,json=onGround,proto3" json:"on_ground,omitempty"`
+	Hovering          bool    `protobuf:"varint,8,opt,name=hovering,proto3" json:"hovering,omitempty"`
+	NorthSpeed        int32   `protobuf:"varint,9,opt,name=north_speed,json=northSpeed,proto3" json:"north_speed,omitempty"`
+	EastSpeed         int32   `protobuf:"varint,10,opt,name=east_speed,json=eastSpeed,proto3" json:"east_speed,omitempty"`
+	VerticalSpeed     int32   `protobuf:"varint,11,opt,name=vertical_speed,json=verticalSpeed,proto3" json:"vertical_speed,omitempty"`
+	PosX              float32 `protobuf:"fixed32,12,opt,name=pos_x,json=posX,proto3" json:"pos_x,omitempty"`
+	PosY              float32 `protobuf:"fixed32,13,opt,name=pos_y,json=posY,proto3" json:"pos_y,omitempty"`
+	PosZ              float32 `protobuf:"fixed32,14,opt,name=pos_z,json=posZ,proto3" json:"pos_z,omitempty"`
+	Yaw               int32   `protobuf:"varint,15,opt,name=yaw,proto3" json:"yaw,omitempty"`
+	Temperature       int32   `protobuf:"varint,16,opt,name=temperature,proto3" json:"temperature,omitempty"`
+}
+
+func (x *FlightData) Reset() {
+	*x = FlightData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tellopb_telloterm_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FlightData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FlightData) ProtoMessage() {}
+
+func (x *FlightData) ProtoReflect() protoreflect.Message {
+	mi := &file_tellopb_telloterm_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FlightData.ProtoReflect.Descriptor instead.
+func (*FlightData) Descriptor() ([]byte, []int) {
+	return file_tellopb_telloterm_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *FlightData) GetTimeUnixMs() int64 {
+	if x != nil {
+		return x.TimeUnixMs
+	}
+	return 0
+}
+
+func (x *FlightData) GetHeightM() float32 {
+	if x != nil {
+		return x.HeightM
+	}
+	return 0
+}
+
+func (x *FlightData) GetBatteryPercent() int32 {
+	if x != nil {
+		return x.BatteryPercent
+	}
+	return 0
+}
+
+func (x *FlightData) GetBatteryMillivolts() int32 {
+	if x != nil {
+		return x.BatteryMillivolts
+	}
+	return 0
+}
+
+func (x *FlightData) GetWifiStrength() int32 {
+	if x != nil {
+		return x.WifiStrength
+	}
+	return 0
+}
+
+func (x *FlightData) GetFlying() bool {
+	if x != nil {
+		return x.Flying
+	}
+	return false
+}
+
+func (x *FlightData) GetOnGround() bool {
+	if x != nil {
+		return x.OnGround
+	}
+	return false
+}
+
+func (x *FlightData) GetHovering() bool {
+	if x != nil {
+		return x.Hovering
+	}
+	return false
+}
+
+func (x *FlightData) GetNorthSpeed() int32 {
+	if x != nil {
+		return x.NorthSpeed
+	}
+	return 0
+}
+
+func (x *FlightData) GetEastSpeed() int32 {
+	if x != nil {
+		return x.EastSpeed
+	}
+	return 0
+}
+
+func (x *FlightData) GetVerticalSpeed() int32 {
+	if x != nil {
+		return x.VerticalSpeed
+	}
+	return 0
+}
+
+func (x *FlightData) GetPosX() float32 {
+	if x != nil {
+		return x.PosX
+	}
+	return 0
+}
+
+func (x *FlightData) GetPosY() float32 {
+	if x != nil {
+		return x.PosY
+	}
+	return 0
+}
+
+func (x *FlightData) GetPosZ() float32 {
+	if x != nil {
+		return x.PosZ
+	}
+	return 0
+}
+
+func (x *FlightData) GetYaw() int32 {
+	if x != nil {
+		return x.Yaw
+	}
+	return 0
+}
+
+func (x *FlightData) GetTemperature() int32 {
+	if x != nil {
+		return x.Temperature
+	}
+	return 0
+}
+
+var File_tellopb_telloterm_proto protoreflect.FileDescriptor
+
+var file_tellopb_telloterm_proto_rawDesc = []byte{
+	0x0a, 0x17, 0x74, 0x65, 0x6c, 0x6c, 0x6f, 0x70, 0x62, 0x2f, 0x74, 0x65, 0x6c, 0x6c, 0x6f, 0x74,
+	0x65, 0x72, 0x6d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09, 0x74, 0x65, 0x6c, 0x6c, 0x6f,
+	0x74, 0x65, 0x72, 0x6d, 0x22, 0x07, 0x0a, 0x05, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x2e, 0x0a,
+	0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x6f, 0x6b, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x02, 0x6f, 0x6b, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x45, 0x0a,
+	0x0b, 0x46, 0x6c, 0x69, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x36, 0x0a, 0x09,
+	0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x18, 0x2e, 0x74, 0x65, 0x6c, 0x6c, 0x6f, 0x74, 0x65, 0x72, 0x6d, 0x2e, 0x46, 0x6c, 0x69, 0x70,
+	0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x22, 0x2a, 0x0a, 0x0c, 0x46, 0x6c, 0x79, 0x54, 0x6f, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x0c, 0x0a, 0x01, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x02, 0x52,
+	0x01, 0x78, 0x12, 0x0c, 0x0a, 0x01, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x02, 0x52, 0x01, 0x79,
+	0x22, 0x2c, 0x0a, 0x0d, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x5f, 0x6d, 0x73, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x4d, 0x73, 0x22, 0xf1,
+	0x03, 0x0a, 0x0a, 0x46, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x44, 0x61, 0x74, 0x61, 0x12, 0x20, 0x0a,
+	0x0c, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x75, 0x6e, 0x69, 0x78, 0x5f, 0x6d, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x0a, 0x74, 0x69, 0x6d, 0x65, 0x55, 0x6e, 0x69, 0x78, 0x4d, 0x73, 0x12,
+	0x19, 0x0a, 0x08, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x5f, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x02, 0x52, 0x07, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x4d, 0x12, 0x27, 0x0a, 0x0f, 0x62, 0x61,
+	0x74, 0x74, 0x65, 0x72, 0x79, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0e, 0x62, 0x61, 0x74, 0x74, 0x65, 0x72, 0x79, 0x50, 0x65, 0x72, 0x63,
+	0x65, 0x6e, 0x74, 0x12, 0x2d, 0x0a, 0x12, 0x62, 0x61, 0x74, 0x74, 0x65, 0x72, 0x79, 0x5f, 0x6d,
+	0x69, 0x6c, 0x6c, 0x69, 0x76, 0x6f, 0x6c, 0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x11, 0x62, 0x61, 0x74, 0x74, 0x65, 0x72, 0x79, 0x4d, 0x69, 0x6c, 0x6c, 0x69, 0x76, 0x6f, 0x6c,
+	0x74, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x77, 0x69, 0x66, 0x69, 0x5f, 0x73, 0x74, 0x72, 0x65, 0x6e,
+	0x67, 0x74, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x77, 0x69, 0x66, 0x69, 0x53,
+	0x74, 0x72, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6c, 0x79, 0x69, 0x6e,
+	0x67, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x66, 0x6c, 0x79, 0x69, 0x6e, 0x67, 0x12,
+	0x1b, 0x0a, 0x09, 0x6f, 0x6e, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x08, 0x6f, 0x6e, 0x47, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x12, 0x1a, 0x0a, 0x08,
+	0x68, 0x6f, 0x76, 0x65, 0x72, 0x69, 0x6e, 0x67, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08,
+	0x68, 0x6f, 0x76, 0x65, 0x72, 0x69, 0x6e, 0x67, 0x12, 0x1f, 0x0a, 0x0b, 0x6e, 0x6f, 0x72, 0x74,
+	0x68, 0x5f, 0x73, 0x70, 0x65, 0x65, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x6e,
+	0x6f, 0x72, 0x74, 0x68, 0x53, 0x70, 0x65, 0x65, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x61, 0x73,
+	0x74, 0x5f, 0x73, 0x70, 0x65, 0x65, 0x64, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x65,
+	0x61, 0x73, 0x74, 0x53, 0x70, 0x65, 0x65, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x76, 0x65, 0x72, 0x74,
+	0x69, 0x63, 0x61, 0x6c, 0x5f, 0x73, 0x70, 0x65, 0x65, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0d, 0x76, 0x65, 0x72, 0x74, 0x69, 0x63, 0x61, 0x6c, 0x53, 0x70, 0x65, 0x65, 0x64, 0x12,
+	0x13, 0x0a, 0x05, 0x70, 0x6f, 0x73, 0x5f, 0x78, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x02, 0x52, 0x04,
+	0x70, 0x6f, 0x73, 0x58, 0x12, 0x13, 0x0a, 0x05, 0x70, 0x6f, 0x73, 0x5f, 0x79, 0x18, 0x0d, 0x20,
+	0x01, 0x28, 0x02, 0x52, 0x04, 0x70, 0x6f, 0x73, 0x59, 0x12, 0x13, 0x0a, 0x05, 0x70, 0x6f, 0x73,
+	0x5f, 0x7a, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x02, 0x52, 0x04, 0x70, 0x6f, 0x73, 0x5a, 0x12, 0x10,
+	0x0a, 0x03, 0x79, 0x61, 0x77, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x79, 0x61, 0x77,
+	0x12, 0x20, 0x0a, 0x0b, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18,
+	0x10, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75,
+	0x72, 0x65, 0x2a, 0x4f, 0x0a, 0x0d, 0x46, 0x6c, 0x69, 0x70, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x10, 0x0a, 0x0c, 0x46, 0x4c, 0x49, 0x50, 0x5f, 0x46, 0x4f, 0x52, 0x57,
+	0x41, 0x52, 0x44, 0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09, 0x46, 0x4c, 0x49, 0x50, 0x5f, 0x42, 0x41,
+	0x43, 0x4b, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x46, 0x4c, 0x49, 0x50, 0x5f, 0x4c, 0x45, 0x46,
+	0x54, 0x10, 0x02, 0x12, 0x0e, 0x0a, 0x0a, 0x46, 0x4c, 0x49, 0x50, 0x5f, 0x52, 0x49, 0x47, 0x48,
+	0x54, 0x10, 0x03, 0x32, 0xbe, 0x04, 0x0a, 0x09, 0x54, 0x65, 0x6c, 0x6c, 0x6f, 0x54, 0x65, 0x72,
+	0x6d, 0x12, 0x2e, 0x0a, 0x07, 0x54, 0x61, 0x6b, 0x65, 0x4f, 0x66, 0x66, 0x12, 0x10, 0x2e, 0x74,
+	0x65, 0x6c, 0x6c, 0x6f, 0x74, 0x65, 0x72, 0x6d, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x11,
+	0x2e, 0x74, 0x65, 0x6c, 0x6c, 0x6f, 0x74, 0x65, 0x72, 0x6d, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x12, 0x33, 0x0a, 0x0c, 0x54, 0x68, 0x72, 0x6f, 0x77, 0x54, 0x61, 0x6b, 0x65, 0x4f, 0x66,
+	0x66, 0x12, 0x10, 0x2e, 0x74, 0x65, 0x6c, 0x6c, 0x6f, 0x74, 0x65, 0x72, 0x6d, 0x2e, 0x45, 0x6d,
+	0x70, 0x74, 0x79, 0x1a, 0x11, 0x2e, 0x74, 0x65, 0x6c, 0x6c, 0x6f, 0x74, 0x65, 0x72, 0x6d, 0x2e,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x2b, 0x0a, 0x04, 0x4c, 0x61, 0x6e, 0x64, 0x12, 0x10,
+	0x2e, 0x74, 0x65, 0x6c, 0x6c, 0x6f, 0x74, 0x65, 0x72, 0x6d, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
+	0x1a, 0x11, 0x2e, 0x74, 0x65, 0x6c, 0x6c, 0x6f, 0x74, 0x65, 0x72, 0x6d, 0x2e, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x12, 0x2f, 0x0a, 0x08, 0x50, 0x61, 0x6c, 0x6d, 0x4c, 0x61, 0x6e, 0x64, 0x12,
+	0x10, 0x2e, 0x74, 0x65, 0x6c, 0x6c, 0x6f, 0x74, 0x65, 0x72, 0x6d, 0x2e, 0x45, 0x6d, 0x70, 0x74,
+	0x79, 0x1a, 0x11, 0x2e, 0x74, 0x65, 0x6c, 0x6c, 0x6f, 0x74, 0x65, 0x72, 0x6d, 0x2e, 0x52, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x12, 0x2c, 0x0a, 0x05, 0x48, 0x6f, 0x76, 0x65, 0x72, 0x12, 0x10, 0x2e,
+	0x74, 0x65, 0x6c, 0x6c, 0x6f, 0x74, 0x65, 0x72, 0x6d, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a,
+	0x11, 0x2e, 0x74, 0x65, 0x6c, 0x6c, 0x6f, 0x74, 0x65, 0x72, 0x6d, 0x2e, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x12, 0x2d, 0x0a, 0x06, 0x42, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x12, 0x10, 0x2e, 0x74,
+	0x65, 0x6c, 0x6c, 0x6f, 0x74, 0x65, 0x72, 0x6d, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x11,
+	0x2e, 0x74, 0x65, 0x6c, 0x6c, 0x6f, 0x74, 0x65, 0x72, 0x6d, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x12, 0x32, 0x0a, 0x0b, 0x54, 0x61, 0x6b, 0x65, 0x50, 0x69, 0x63, 0x74, 0x75, 0x72, 0x65,
+	0x12, 0x10, 0x2e, 0x74, 0x65, 0x6c, 0x6c, 0x6f, 0x74, 0x65, 0x72, 0x6d, 0x2e, 0x45, 0x6d, 0x70,
+	0x74, 0x79, 0x1a, 0x11, 0x2e, 0x74, 0x65, 0x6c, 0x6c, 0x6f, 0x74, 0x65, 0x72, 0x6d, 0x2e, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x31, 0x0a, 0x04, 0x46, 0x6c, 0x69, 0x70, 0x12, 0x16, 0x2e,
+	0x74, 0x65, 0x6c, 0x6c, 0x6f, 0x74, 0x65, 0x72, 0x6d, 0x2e, 0x46, 0x6c, 0x69, 0x70, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x74, 0x65, 0x6c, 0x6c, 0x6f, 0x74, 0x65, 0x72,
+	0x6d, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x2e, 0x0a, 0x07, 0x53, 0x65, 0x74, 0x48,
+	0x6f, 0x6d, 0x65, 0x12, 0x10, 0x2e, 0x74, 0x65, 0x6c, 0x6c, 0x6f, 0x74, 0x65, 0x72, 0x6d, 0x2e,
+	0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x11, 0x2e, 0x74, 0x65, 0x6c, 0x6c, 0x6f, 0x74, 0x65, 0x72,
+	0x6d, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x33, 0x0a, 0x05, 0x46, 0x6c, 0x79, 0x54,
+	0x6f, 0x12, 0x17, 0x2e, 0x74, 0x65, 0x6c, 0x6c, 0x6f, 0x74, 0x65, 0x72, 0x6d, 0x2e, 0x46, 0x6c,
+	0x79, 0x54, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x74, 0x65, 0x6c,
+	0x6c, 0x6f, 0x74, 0x65, 0x72, 0x6d, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x45, 0x0a,
+	0x10, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x46, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x44, 0x61, 0x74,
+	0x61, 0x12, 0x18, 0x2e, 0x74, 0x65, 0x6c, 0x6c, 0x6f, 0x74, 0x65, 0x72, 0x6d, 0x2e, 0x53, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x74, 0x65,
+	0x6c, 0x6c, 0x6f, 0x74, 0x65, 0x72, 0x6d, 0x2e, 0x46, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x44, 0x61,
+	0x74, 0x61, 0x30, 0x01, 0x42, 0x27, 0x5a, 0x25, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x53, 0x4d, 0x65, 0x72, 0x72, 0x6f, 0x6e, 0x79, 0x2f, 0x74, 0x65, 0x6c, 0x6c,
+	0x6f, 0x74, 0x65, 0x72, 0x6d, 0x2f, 0x74, 0x65, 0x6c, 0x6c, 0x6f, 0x70, 0x62, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_tellopb_telloterm_proto_rawDescOnce sync.Once
+	file_tellopb_telloterm_proto_rawDescData = file_tellopb_telloterm_proto_rawDesc
+)
+
+func file_tellopb_telloterm_proto_rawDescGZIP() []byte {
+	file_tellopb_telloterm_proto_rawDescOnce.Do(func() {
+		file_tellopb_telloterm_proto_rawDescData = protoimpl.X.CompressGZIP(file_tellopb_telloterm_proto_rawDescData)
+	})
+	return file_tellopb_telloterm_proto_rawDescData
+}
+
+var file_tellopb_telloterm_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_tellopb_telloterm_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_tellopb_telloterm_proto_goTypes = []any{
+	(FlipDirection)(0),    // 0: telloterm.FlipDirection
+	(*Empty)(nil),         // 1: telloterm.Empty
+	(*Result)(nil),        // 2: telloterm.Result
+	(*FlipRequest)(nil),   // 3: telloterm.FlipRequest
+	(*FlyToRequest)(nil),  // 4: telloterm.FlyToRequest
+	(*StreamRequest)(nil), // 5: telloterm.StreamRequest
+	(*FlightData)(nil),    // 6: telloterm.FlightData
+}
+var file_tellopb_telloterm_proto_depIdxs = []int32{
+	0,  // 0: telloterm.FlipRequest.direction:type_name -> telloterm.FlipDirection
+	1,  // 1: telloterm.TelloTerm.TakeOff:input_type -> telloterm.Empty
+	1,  // 2: telloterm.TelloTerm.ThrowTakeOff:input_type -> telloterm.Empty
+	1,  // 3: telloterm.TelloTerm.Land:input_type -> telloterm.Empty
+	1,  // 4: telloterm.TelloTerm.PalmLand:input_type -> telloterm.Empty
+	1,  // 5: telloterm.TelloTerm.Hover:input_type -> telloterm.Empty
+	1,  // 6: telloterm.TelloTerm.Bounce:input_type -> telloterm.Empty
+	1,  // 7: telloterm.TelloTerm.TakePicture:input_type -> telloterm.Empty
+	3,  // 8: telloterm.TelloTerm.Flip:input_type -> telloterm.FlipRequest
+	1,  // 9: telloterm.TelloTerm.SetHome:input_type -> telloterm.Empty
+	4,  // 10: telloterm.TelloTerm.FlyTo:input_type -> telloterm.FlyToRequest
+	5,  // 11: telloterm.TelloTerm.StreamFlightData:input_type -> telloterm.StreamRequest
+	2,  // 12: telloterm.TelloTerm.TakeOff:output_type -> telloterm.Result
+	2,  // 13: telloterm.TelloTerm.ThrowTakeOff:output_type -> telloterm.Result
+	2,  // 14: telloterm.TelloTerm.Land:output_type -> telloterm.Result
+	2,  // 15: telloterm.TelloTerm.PalmLand:output_type -> telloterm.Result
+	2,  // 16: telloterm.TelloTerm.Hover:output_type -> telloterm.Result
+	2,  // 17: telloterm.TelloTerm.Bounce:output_type -> telloterm.Result
+	2,  // 18: telloterm.TelloTerm.TakePicture:output_type -> telloterm.Result
+	2,  // 19: telloterm.TelloTerm.Flip:output_type -> telloterm.Result
+	2,  // 20: telloterm.TelloTerm.SetHome:output_type -> telloterm.Result
+	2,  // 21: telloterm.TelloTerm.FlyTo:output_type -> telloterm.Result
+	6,  // 22: telloterm.TelloTerm.StreamFlightData:output_type -> telloterm.FlightData
+	12, // [12:23] is the sub-list for method output_type
+	1,  // [1:12] is the sub-list for method input_type
+	1,  // [1:1] is the sub-list for extension type_name
+	1,  // [1:1] is the sub-list for extension extendee
+	0,  // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_tellopb_telloterm_proto_init() }
+func file_tellopb_telloterm_proto_init() {
+	if File_tellopb_telloterm_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_tellopb_telloterm_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*Empty); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tellopb_telloterm_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*Result); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tellopb_telloterm_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*FlipRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tellopb_telloterm_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*FlyToRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tellopb_telloterm_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*StreamRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tellopb_telloterm_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*FlightData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_tellopb_telloterm_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_tellopb_telloterm_proto_goTypes,
+		DependencyIndexes: file_tellopb_telloterm_proto_depIdxs,
+		EnumInfos:         file_tellopb_telloterm_proto_enumTypes,
+		MessageInfos:      file_tellopb_telloterm_proto_msgTypes,
+	}.Build()
+	File_tellopb_telloterm_proto = out.File
+	file_tellopb_telloterm_proto_rawDesc = nil
+	file_tellopb_telloterm_proto_goTypes = nil
+	file_tellopb_telloterm_proto_depIdxs = nil
+}
@@ -0,0 +1,513 @@
+// Control and telemetry service offered by telloterm when started with -grpc.
+//
+// Regenerate the Go stubs with
+//   go generate -tags grpc
+// which needs protoc, protoc-gen-go and protoc-gen-go-grpc on your PATH.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: tellopb/telloterm.proto
+
+package tellopb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	TelloTerm_TakeOff_FullMethodName          = "/telloterm.TelloTerm/TakeOff"
+	TelloTerm_ThrowTakeOff_FullMethodName     = "/telloterm.TelloTerm/ThrowTakeOff"
+	TelloTerm_Land_FullMethodName             = "/telloterm.TelloTerm/Land"
+	TelloTerm_PalmLand_FullMethodName         = "/telloterm.TelloTerm/PalmLand"
+	TelloTerm_Hover_FullMethodName            = "/telloterm.TelloTerm/Hover"
+	TelloTerm_Bounce_FullMethodName           = "/telloterm.TelloTerm/Bounce"
+	TelloTerm_TakePicture_FullMethodName      = "/telloterm.TelloTerm/TakePicture"
+	TelloTerm_Flip_FullMethodName             = "/telloterm.TelloTerm/Flip"
+	TelloTerm_SetHome_FullMethodName          = "/telloterm.TelloTerm/SetHome"
+	TelloTerm_FlyTo_FullMethodName            = "/telloterm.TelloTerm/FlyTo"
+	TelloTerm_StreamFlightData_FullMethodName = "/telloterm.TelloTerm/StreamFlightData"
+)
+
+// TelloTermClient is the client API for TelloTerm service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TelloTermClient interface {
+	TakeOff(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Result, error)
+	ThrowTakeOff(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Result, error)
+	Land(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Result, error)
+	PalmLand(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Result, error)
+	Hover(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Result, error)
+	Bounce(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Result, error)
+	TakePicture(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Result, error)
+	Flip(ctx context.Context, in *FlipRequest, opts ...grpc.CallOption) (*Result, error)
+	SetHome(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Result, error)
+	FlyTo(ctx context.Context, in *FlyToRequest, opts ...grpc.CallOption) (*Result, error)
+	// StreamFlightData sends a snapshot of the latest flight data every period_ms
+	StreamFlightData(ctx context.Context, in *StreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FlightData], error)
+}
+
+type telloTermClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTelloTermClient(cc grpc.ClientConnInterface) TelloTermClient {
+	return &telloTermClient{cc}
+}
+
+func (c *telloTermClient) TakeOff(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Result, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Result)
+	err := c.cc.Invoke(ctx, TelloTerm_TakeOff_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *telloTermClient) ThrowTakeOff(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Result, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Result)
+	err := c.cc.Invoke(ctx, TelloTerm_ThrowTakeOff_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *telloTermClient) Land(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Result, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Result)
+	err := c.cc.Invoke(ctx, TelloTerm_Land_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *telloTermClient) PalmLand(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Result, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Result)
+	err := c.cc.Invoke(ctx, TelloTerm_PalmLand_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *telloTermClient) Hover(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Result, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Result)
+	err := c.cc.Invoke(ctx, TelloTerm_Hover_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *telloTermClient) Bounce(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Result, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Result)
+	err := c.cc.Invoke(ctx, TelloTerm_Bounce_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *telloTermClient) TakePicture(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Result, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Result)
+	err := c.cc.Invoke(ctx, TelloTerm_TakePicture_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *telloTermClient) Flip(ctx context.Context, in *FlipRequest, opts ...grpc.CallOption) (*Result, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Result)
+	err := c.cc.Invoke(ctx, TelloTerm_Flip_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *telloTermClient) SetHome(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Result, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Result)
+	err := c.cc.Invoke(ctx, TelloTerm_SetHome_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *telloTermClient) FlyTo(ctx context.Context, in *FlyToRequest, opts ...grpc.CallOption) (*Result, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Result)
+	err := c.cc.Invoke(ctx, TelloTerm_FlyTo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *telloTermClient) StreamFlightData(ctx context.Context, in *StreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FlightData], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TelloTerm_ServiceDesc.Streams[0], TelloTerm_StreamFlightData_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamRequest, FlightData]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TelloTerm_StreamFlightDataClient = grpc.ServerStreamingClient[FlightData]
+
+// TelloTermServer is the server API for TelloTerm service.
+// All implementations must embed UnimplementedTelloTermServer
+// for forward compatibility.
+type TelloTermServer interface {
+	TakeOff(context.Context, *Empty) (*Result, error)
+	ThrowTakeOff(context.Context, *Empty) (*Result, error)
+	Land(context.Context, *Empty) (*Result, error)
+	PalmLand(context.Context, *Empty) (*Result, error)
+	Hover(context.Context, *Empty) (*Result, error)
+	Bounce(context.Context, *Empty) (*Result, error)
+	TakePicture(context.Context, *Empty) (*Result, error)
+	Flip(context.Context, *FlipRequest) (*Result, error)
+	SetHome(context.Context, *Empty) (*Result, error)
+	FlyTo(context.Context, *FlyToRequest) (*Result, error)
+	// StreamFlightData sends a snapshot of the latest flight data every period_ms
+	StreamFlightData(*StreamRequest, grpc.ServerStreamingServer[FlightData]) error
+	mustEmbedUnimplementedTelloTermServer()
+}
+
+// UnimplementedTelloTermServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTelloTermServer struct{}
+
+func (UnimplementedTelloTermServer) TakeOff(context.Context, *Empty) (*Result, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TakeOff not implemented")
+}
+func (UnimplementedTelloTermServer) ThrowTakeOff(context.Context, *Empty) (*Result, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ThrowTakeOff not implemented")
+}
+func (UnimplementedTelloTermServer) Land(context.Context, *Empty) (*Result, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Land not implemented")
+}
+func (UnimplementedTelloTermServer) PalmLand(context.Context, *Empty) (*Result, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PalmLand not implemented")
+}
+func (UnimplementedTelloTermServer) Hover(context.Context, *Empty) (*Result, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Hover not implemented")
+}
+func (UnimplementedTelloTermServer) Bounce(context.Context, *Empty) (*Result, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Bounce not implemented")
+}
+func (UnimplementedTelloTermServer) TakePicture(context.Context, *Empty) (*Result, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TakePicture not implemented")
+}
+func (UnimplementedTelloTermServer) Flip(context.Context, *FlipRequest) (*Result, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Flip not implemented")
+}
+func (UnimplementedTelloTermServer) SetHome(context.Context, *Empty) (*Result, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetHome not implemented")
+}
+func (UnimplementedTelloTermServer) FlyTo(context.Context, *FlyToRequest) (*Result, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FlyTo not implemented")
+}
+func (UnimplementedTelloTermServer) StreamFlightData(*StreamRequest, grpc.ServerStreamingServer[FlightData]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamFlightData not implemented")
+}
+func (UnimplementedTelloTermServer) mustEmbedUnimplementedTelloTermServer() {}
+func (UnimplementedTelloTermServer) testEmbeddedByValue()                   {}
+
+// UnsafeTelloTermServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TelloTermServer will
+// result in compilation errors.
+type UnsafeTelloTermServer interface {
+	mustEmbedUnimplementedTelloTermServer()
+}
+
+func RegisterTelloTermServer(s grpc.ServiceRegistrar, srv TelloTermServer) {
+	// If the following call pancis, it indicates UnimplementedTelloTermServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TelloTerm_ServiceDesc, srv)
+}
+
+func _TelloTerm_TakeOff_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TelloTermServer).TakeOff(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TelloTerm_TakeOff_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TelloTermServer).TakeOff(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TelloTerm_ThrowTakeOff_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TelloTermServer).ThrowTakeOff(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TelloTerm_ThrowTakeOff_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TelloTermServer).ThrowTakeOff(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TelloTerm_Land_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TelloTermServer).Land(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TelloTerm_Land_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TelloTermServer).Land(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TelloTerm_PalmLand_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TelloTermServer).PalmLand(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TelloTerm_PalmLand_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TelloTermServer).PalmLand(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TelloTerm_Hover_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TelloTermServer).Hover(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TelloTerm_Hover_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TelloTermServer).Hover(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TelloTerm_Bounce_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TelloTermServer).Bounce(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TelloTerm_Bounce_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TelloTermServer).Bounce(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TelloTerm_TakePicture_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TelloTermServer).TakePicture(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TelloTerm_TakePicture_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TelloTermServer).TakePicture(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TelloTerm_Flip_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FlipRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TelloTermServer).Flip(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TelloTerm_Flip_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TelloTermServer).Flip(ctx, req.(*FlipRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TelloTerm_SetHome_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TelloTermServer).SetHome(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TelloTerm_SetHome_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TelloTermServer).SetHome(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TelloTerm_FlyTo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FlyToRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TelloTermServer).FlyTo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TelloTerm_FlyTo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TelloTermServer).FlyTo(ctx, req.(*FlyToRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TelloTerm_StreamFlightData_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TelloTermServer).StreamFlightData(m, &grpc.GenericServerStream[StreamRequest, FlightData]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TelloTerm_StreamFlightDataServer = grpc.ServerStreamingServer[FlightData]
+
+// TelloTerm_ServiceDesc is the grpc.ServiceDesc for TelloTerm service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TelloTerm_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "telloterm.TelloTerm",
+	HandlerType: (*TelloTermServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "TakeOff",
+			Handler:    _TelloTerm_TakeOff_Handler,
+		},
+		{
+			MethodName: "ThrowTakeOff",
+			Handler:    _TelloTerm_ThrowTakeOff_Handler,
+		},
+		{
+			MethodName: "Land",
+			Handler:    _TelloTerm_Land_Handler,
+		},
+		{
+			MethodName: "PalmLand",
+			Handler:    _TelloTerm_PalmLand_Handler,
+		},
+		{
+			MethodName: "Hover",
+			Handler:    _TelloTerm_Hover_Handler,
+		},
+		{
+			MethodName: "Bounce",
+			Handler:    _TelloTerm_Bounce_Handler,
+		},
+		{
+			MethodName: "TakePicture",
+			Handler:    _TelloTerm_TakePicture_Handler,
+		},
+		{
+			MethodName: "Flip",
+			Handler:    _TelloTerm_Flip_Handler,
+		},
+		{
+			MethodName: "SetHome",
+			Handler:    _TelloTerm_SetHome_Handler,
+		},
+		{
+			MethodName: "FlyTo",
+			Handler:    _TelloTerm_FlyTo_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamFlightData",
+			Handler:       _TelloTerm_StreamFlightData_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "tellopb/telloterm.proto",
+}
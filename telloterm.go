@@ -23,9 +23,10 @@
 package main
 
 import (
-	"encoding/csv"
+	"context"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"math"
 	"os"
@@ -45,6 +46,10 @@ const (
 	minHeight      = 24
 	updatePeriodMs = 50
 	keyPct         = 33 // default speed setting from keyboard control
+	maxTempC       = 80 // IMU temperature above which we consider the drone overheated
+	linkTimeout    = 2 * time.Second
+	flashTime      = 2 * updatePeriodMs * time.Millisecond // two display frames
+	hoverDebounce  = 500 * time.Millisecond
 )
 
 type label struct {
@@ -53,11 +58,7 @@ type label struct {
 	text   string
 }
 
-var staticLabels = []label{
-	label{33, 0, termbox.ColorWhite | termbox.AttrReverse, termbox.ColorDefault, "TelloTerm"},
-	label{33, 14, termbox.ColorWhite | termbox.AttrBold, termbox.ColorDefault, "MVO Data"},
-	label{33, 17, termbox.ColorWhite | termbox.AttrBold, termbox.ColorDefault, "IMU Data"},
-}
+var staticLabels []label
 
 type field struct {
 	lab    label
@@ -79,9 +80,8 @@ const (
 	fFwdSpeed
 	fLatSpeed
 	fVertSpeed
-	fBattLow
-	fBattCrit
-	fBattState
+	fBattStatus
+	fChecklist
 	fGroundVis
 	fErrorState
 	fLightStrength
@@ -92,12 +92,30 @@ const (
 	fCameraState
 	fDroneFlyTimeLeft
 	fDroneBattLeft
+	fBattETA
+	fCellVolts
+	fBattHealth
+	fOdometer
+	fToggleKey
+	fBounce
+	fPeakHeight
+	fPeakSpeed
+	fFlightState
+	fMVOStatus
+	fLink
+	fVideo
+	fVideoFPS
+	fLinkLag
+	fSpeedMode
+	fTimelapse
+	fManeuver
 	fVelX
 	fVelY
 	fVelZ
 	fPosX
 	fPosY
 	fPosZ
+	fPosZero
 	fQatW
 	fQatX
 	fQatY
@@ -107,99 +125,257 @@ const (
 	fPitch
 	fYaw
 	fHome
+	fHomeKey
 	fSSID
 	fVersion
+	fMessage
 	fNumFields
 )
 
 var fieldsMu sync.RWMutex
 var fields [fNumFields]field
 
+// nonVolatile marks fields which are only drawn when their value changes
+// rather than every frame
+var nonVolatile = [fNumFields]bool{fSSID: true, fVersion: true}
+
+var (
+	staticMu    sync.Mutex
+	drawnStatic [fNumFields]string // the nonVolatile values on screen, "" if not drawn
+)
+
+// critical marks fields currently in an emergency state, only those listed
+// in blinkFields are ever flashed
+var critical [fNumFields]bool
+var blinkFields = []int{fBattStatus, fTemp, fLink, fWifiInterference}
+
+const blinkPeriod = 400 * time.Millisecond // a few redraws per phase, not 10Hz flashing
+
 func setupFields() {
-	fields[fHeight] = field{label{8, 2, termbox.ColorWhite, termbox.ColorDefault, "Height:"}, 16, 2, 5, termbox.ColorWhite, termbox.ColorDefault, "?m"}
-	fields[fBattery] = field{label{34, 2, termbox.ColorWhite, termbox.ColorDefault, "Battery:"}, 43, 2, 4, termbox.ColorWhite, termbox.ColorDefault, "?%"}
-	fields[fWifiStrength] = field{label{61, 2, termbox.ColorWhite, termbox.ColorDefault, "WiFi:"}, 67, 2, 4, termbox.ColorWhite, termbox.ColorDefault, "?%"}
+	staticLabels = []label{
+		label{33, 0, th.Title, th.Background, "TelloTerm"},
+		label{33, 14, th.Heading, th.Background, "MVO Data"},
+		label{33, 17, th.Heading, th.Background, "IMU Data"},
+	}
+
+	fields[fLink] = field{label{44, 0, th.Label, th.Background, "Link:"}, 50, 0, 10, th.Caution, th.Background, "CONNECTING"}
+	fields[fVideo] = field{label{62, 0, th.Label, th.Background, "Video:"}, 69, 0, 3, th.Bad, th.Background, "OFF"}
+	fields[fVideoFPS] = field{label{73, 0, th.Label, th.Background, ""}, 73, 0, 6, th.Value, th.Background, ""}
+	fields[fLinkLag] = field{label{45, 1, th.Derived, th.Background, "Lag:"}, 50, 1, 7, th.Value, th.Background, "?"}
 
-	fields[fMaxHeight] = field{label{4, 3, termbox.ColorWhite, termbox.ColorDefault, "Max Height:"}, 16, 3, 5, termbox.ColorWhite, termbox.ColorDefault, "?m"}
-	fields[fDroneBattLeft] = field{label{34, 3, termbox.ColorWhite, termbox.ColorDefault, "Voltage:"}, 43, 3, 6, termbox.ColorWhite, termbox.ColorDefault, "?"}
-	fields[fWifiInterference] = field{label{53, 3, termbox.ColorWhite, termbox.ColorDefault, "Interference:"}, 67, 3, 4, termbox.ColorWhite, termbox.ColorDefault, "?%"}
+	fields[fHeight] = field{label{8, 2, th.Label, th.Background, "Height:"}, 16, 2, 5, th.Value, th.Background, "?m"}
+	fields[fBattery] = field{label{34, 2, th.Label, th.Background, "Battery:"}, 43, 2, 4, th.Value, th.Background, "?%"}
+	fields[fWifiStrength] = field{label{61, 2, th.Label, th.Background, "WiFi:"}, 67, 2, 4, th.Value, th.Background, "?%"}
 
-	fields[fLowBattThresh] = field{label{24, 4, termbox.ColorWhite, termbox.ColorDefault, "Lo Batt Threshold:"}, 43, 4, 4, termbox.ColorWhite, termbox.ColorDefault, "?%"}
+	fields[fMaxHeight] = field{label{4, 3, th.Label, th.Background, "Max Height:"}, 16, 3, 5, th.Value, th.Background, "?m"}
+	fields[fDroneBattLeft] = field{label{34, 3, th.Label, th.Background, "Voltage:"}, 43, 3, 6, th.Value, th.Background, "?"}
+	fields[fWifiInterference] = field{label{53, 3, th.Label, th.Background, "Interference:"}, 67, 3, 4, th.Value, th.Background, "?%"}
 
-	fields[fDerivedSpeed] = field{label{28, 6, termbox.ColorYellow, termbox.ColorDefault, "Derived Speed:"}, 43, 6, 7, termbox.ColorWhite, termbox.ColorDefault, "?m/s"}
-	fields[fVertSpeed] = field{label{51, 6, termbox.ColorWhite, termbox.ColorDefault, "Vertical Speed:"}, 67, 6, 7, termbox.ColorWhite, termbox.ColorDefault, "?m/s"}
+	fields[fLowBattThresh] = field{label{24, 4, th.Label, th.Background, "Lo Batt Threshold:"}, 43, 4, 4, th.Value, th.Background, "?%"}
+	fields[fCellVolts] = field{label{49, 4, th.Derived, th.Background, "Cell:"}, 55, 4, 5, th.Value, th.Background, "?V"}
+	fields[fBattHealth] = field{label{61, 4, th.Derived, th.Background, "Health:"}, 69, 4, 4, th.Value, th.Background, "?"}
 
-	fields[fGroundSpeed] = field{label{2, 7, termbox.ColorWhite, termbox.ColorDefault, "Ground Speed:"}, 16, 7, 5, termbox.ColorWhite, termbox.ColorDefault, "?m/s"}
-	fields[fFwdSpeed] = field{label{28, 7, termbox.ColorWhite, termbox.ColorDefault, "Forward Speed:"}, 43, 7, 5, termbox.ColorWhite, termbox.ColorDefault, "?m/s"}
-	fields[fLatSpeed] = field{label{52, 7, termbox.ColorWhite, termbox.ColorDefault, "Lateral Speed:"}, 67, 7, 5, termbox.ColorWhite, termbox.ColorDefault, "?m/s"}
+	fields[fSpeedMode] = field{label{4, 6, th.Label, th.Background, "Speed Mode:"}, 16, 6, 5, th.Value, th.Background, "Slow"}
+	fields[fDerivedSpeed] = field{label{28, 6, th.Derived, th.Background, "Derived Speed:"}, 43, 6, 7, th.Value, th.Background, "?m/s"}
+	fields[fVertSpeed] = field{label{51, 6, th.Label, th.Background, "Vertical Speed:"}, 67, 6, 7, th.Value, th.Background, "?m/s"}
 
-	fields[fBattLow] = field{label{3, 9, termbox.ColorWhite, termbox.ColorDefault, "Battery Low:"}, 16, 9, 5, termbox.ColorWhite, termbox.ColorDefault, "?"}
-	fields[fBattCrit] = field{label{25, 9, termbox.ColorWhite, termbox.ColorDefault, "Battery Critical:"}, 43, 9, 5, termbox.ColorWhite, termbox.ColorDefault, "?"}
-	fields[fBattState] = field{label{52, 9, termbox.ColorWhite, termbox.ColorDefault, "Battery State:"}, 67, 9, 5, termbox.ColorWhite, termbox.ColorDefault, "?"}
+	fields[fToggleKey] = field{label{4, 8, th.Label, th.Background, "Toggle Key:"}, 16, 8, 12, th.Value, th.Background, "?"}
+	fields[fGroundSpeed] = field{label{2, 7, th.Label, th.Background, "Ground Speed:"}, 16, 7, 5, th.Value, th.Background, "?m/s"}
+	fields[fFwdSpeed] = field{label{28, 7, th.Label, th.Background, "Forward Speed:"}, 43, 7, 5, th.Value, th.Background, "?m/s"}
+	fields[fLatSpeed] = field{label{52, 7, th.Label, th.Background, "Lateral Speed:"}, 67, 7, 5, th.Value, th.Background, "?m/s"}
 
-	fields[fGroundVis] = field{label{1, 10, termbox.ColorWhite, termbox.ColorDefault, "Ground Visual:"}, 16, 10, 5, termbox.ColorWhite, termbox.ColorDefault, "?"}
-	fields[fErrorState] = field{label{26, 10, termbox.ColorWhite, termbox.ColorDefault, "Error Condition:"}, 43, 10, 5, termbox.ColorWhite, termbox.ColorDefault, "?"}
-	fields[fLightStrength] = field{label{51, 10, termbox.ColorWhite, termbox.ColorDefault, "Light Strength:"}, 67, 10, 5, termbox.ColorWhite, termbox.ColorDefault, "?"}
+	fields[fBattStatus] = field{label{0, 9, th.Label, th.Background, "Battery Status:"}, 16, 9, 8, th.Value, th.Background, "?"}
+	fields[fChecklist] = field{label{31, 9, th.Derived, th.Background, "Pre-flight:"}, 43, 9, 36, th.Value, th.Background, "?"}
 
-	fields[fOnGround] = field{label{5, 11, termbox.ColorWhite, termbox.ColorDefault, "On Ground:"}, 16, 11, 5, termbox.ColorWhite, termbox.ColorDefault, "?"}
-	fields[fHovering] = field{label{33, 11, termbox.ColorWhite, termbox.ColorDefault, "Hovering:"}, 43, 11, 5, termbox.ColorWhite, termbox.ColorDefault, "?"}
-	fields[fFlying] = field{label{59, 11, termbox.ColorWhite, termbox.ColorDefault, "Flying:"}, 67, 11, 5, termbox.ColorWhite, termbox.ColorDefault, "?"}
+	fields[fGroundVis] = field{label{1, 10, th.Label, th.Background, "Ground Visual:"}, 16, 10, 5, th.Value, th.Background, "?"}
+	fields[fErrorState] = field{label{26, 10, th.Label, th.Background, "Error Condition:"}, 43, 10, 5, th.Value, th.Background, "?"}
+	fields[fLightStrength] = field{label{51, 10, th.Label, th.Background, "Light Strength:"}, 67, 10, 5, th.Value, th.Background, "?"}
 
-	fields[fCameraState] = field{label{2, 12, termbox.ColorWhite, termbox.ColorDefault, "Camera State:"}, 16, 12, 6, termbox.ColorWhite, termbox.ColorDefault, "?"}
-	fields[fFlyMode] = field{label{30, 12, termbox.ColorWhite, termbox.ColorDefault, "Flight Mode:"}, 43, 12, 5, termbox.ColorWhite, termbox.ColorDefault, "?"}
-	fields[fDroneFlyTimeLeft] = field{label{49, 12, termbox.ColorWhite, termbox.ColorDefault, "Flight Remaining:"}, 67, 12, 6, termbox.ColorWhite, termbox.ColorDefault, "?"}
+	fields[fOnGround] = field{label{5, 11, th.Label, th.Background, "On Ground:"}, 16, 11, 5, th.Value, th.Background, "?"}
+	fields[fHovering] = field{label{33, 11, th.Label, th.Background, "Hovering:"}, 43, 11, 5, th.Value, th.Background, "?"}
+	fields[fFlying] = field{label{59, 11, th.Label, th.Background, "Flying:"}, 67, 11, 5, th.Value, th.Background, "?"}
 
-	fields[fVelX] = field{label{4, 15, termbox.ColorWhite, termbox.ColorDefault, "X Velocity:"}, 16, 15, 8, termbox.ColorWhite, termbox.ColorDefault, "?"}
-	fields[fVelY] = field{label{31, 15, termbox.ColorWhite, termbox.ColorDefault, "Y Velocity:"}, 43, 15, 8, termbox.ColorWhite, termbox.ColorDefault, "?"}
-	fields[fVelZ] = field{label{55, 15, termbox.ColorWhite, termbox.ColorDefault, "Z Velocity:"}, 67, 15, 8, termbox.ColorWhite, termbox.ColorDefault, "?"}
+	fields[fCameraState] = field{label{2, 12, th.Label, th.Background, "Camera State:"}, 16, 12, 10, th.Value, th.Background, "?"}
+	fields[fFlyMode] = field{label{28, 12, th.Label, th.Background, "Flight Mode:"}, 41, 12, 8, th.Value, th.Background, "?"}
+	fields[fDroneFlyTimeLeft] = field{label{49, 12, th.Label, th.Background, "Flight Remaining:"}, 67, 12, 6, th.Value, th.Background, "?"}
 
-	fields[fPosX] = field{label{4, 16, termbox.ColorWhite, termbox.ColorDefault, "X Position:"}, 16, 16, 6, termbox.ColorWhite, termbox.ColorDefault, "?"}
-	fields[fPosY] = field{label{31, 16, termbox.ColorWhite, termbox.ColorDefault, "Y Position:"}, 43, 16, 6, termbox.ColorWhite, termbox.ColorDefault, "?"}
-	fields[fPosZ] = field{label{55, 16, termbox.ColorWhite, termbox.ColorDefault, "Z Position:"}, 67, 16, 6, termbox.ColorWhite, termbox.ColorDefault, "?"}
+	fields[fOdometer] = field{label{6, 13, th.Label, th.Background, "Distance:"}, 16, 13, 7, th.Value, th.Background, "?m"}
+	fields[fBattETA] = field{label{51, 13, th.Derived, th.Background, "Est. Remaining:"}, 67, 13, 6, th.Value, th.Background, "?"}
 
-	fields[fQatX] = field{label{8, 18, termbox.ColorWhite, termbox.ColorDefault, "X Quat:"}, 16, 18, 6, termbox.ColorWhite, termbox.ColorDefault, "?"}
-	fields[fQatY] = field{label{35, 18, termbox.ColorWhite, termbox.ColorDefault, "Y Quat:"}, 43, 18, 6, termbox.ColorWhite, termbox.ColorDefault, "?"}
-	fields[fQatZ] = field{label{59, 18, termbox.ColorWhite, termbox.ColorDefault, "Z Quat:"}, 67, 18, 6, termbox.ColorWhite, termbox.ColorDefault, "?"}
+	fields[fMVOStatus] = field{label{43, 14, th.Label, th.Background, ""}, 43, 14, 15, th.Bad | termbox.AttrBold, th.Background, ""}
+	fields[fVelX] = field{label{4, 15, th.Label, th.Background, "X Velocity:"}, 16, 15, 8, th.Value, th.Background, "?"}
+	fields[fVelY] = field{label{31, 15, th.Label, th.Background, "Y Velocity:"}, 43, 15, 8, th.Value, th.Background, "?"}
+	fields[fVelZ] = field{label{55, 15, th.Label, th.Background, "Z Velocity:"}, 67, 15, 8, th.Value, th.Background, "?"}
 
-	fields[fTemp] = field{label{10, 19, termbox.ColorWhite, termbox.ColorDefault, "Temp:"}, 16, 19, 6, termbox.ColorWhite, termbox.ColorDefault, "?"}
-	fields[fQatW] = field{label{35, 19, termbox.ColorWhite, termbox.ColorDefault, "W Quat:"}, 43, 19, 6, termbox.ColorWhite, termbox.ColorDefault, "?"}
-	fields[fYaw] = field{label{62, 19, termbox.ColorYellow, termbox.ColorDefault, "Yaw:"}, 67, 19, 6, termbox.ColorWhite, termbox.ColorDefault, "?°"}
+	fields[fPosX] = field{label{4, 16, th.Label, th.Background, "X Position:"}, 16, 16, 6, th.Value, th.Background, "?"}
+	fields[fPosY] = field{label{31, 16, th.Label, th.Background, "Y Position:"}, 43, 16, 6, th.Value, th.Background, "?"}
+	fields[fPosZ] = field{label{55, 16, th.Label, th.Background, "Z Position:"}, 67, 16, 6, th.Value, th.Background, "?"}
+	fields[fPosZero] = field{label{61, 14, th.Label, th.Background, ""}, 61, 14, 12, th.Caution, th.Background, ""}
 
-	fields[fHome] = field{label{33, 20, termbox.ColorYellow, termbox.ColorDefault, "Home Pos:"}, 43, 20, 5, termbox.ColorWhite, termbox.ColorDefault, "?"}
+	fields[fRoll] = field{label{10, 17, th.Derived, th.Background, "Roll:"}, 16, 17, 6, th.Value, th.Background, "?°"}
+	fields[fPitch] = field{label{60, 17, th.Derived, th.Background, "Pitch:"}, 67, 17, 6, th.Value, th.Background, "?°"}
 
-	fields[fSSID] = field{label{10, 22, termbox.ColorWhite, termbox.ColorDefault, "SSID:"}, 16, 22, 20, termbox.ColorWhite, termbox.ColorDefault, "?"}
-	fields[fVersion] = field{label{57, 22, termbox.ColorWhite, termbox.ColorDefault, "Firmware:"}, 67, 22, 10, termbox.ColorWhite, termbox.ColorDefault, "?"}
+	fields[fQatX] = field{label{8, 18, th.Label, th.Background, "X Quat:"}, 16, 18, 6, th.Value, th.Background, "?"}
+	fields[fQatY] = field{label{35, 18, th.Label, th.Background, "Y Quat:"}, 43, 18, 6, th.Value, th.Background, "?"}
+	fields[fQatZ] = field{label{59, 18, th.Label, th.Background, "Z Quat:"}, 67, 18, 6, th.Value, th.Background, "?"}
+
+	fields[fTemp] = field{label{10, 19, th.Label, th.Background, "Temp:"}, 16, 19, 6, th.Value, th.Background, "?"}
+	fields[fQatW] = field{label{35, 19, th.Label, th.Background, "W Quat:"}, 43, 19, 6, th.Value, th.Background, "?"}
+	fields[fYaw] = field{label{62, 19, th.Derived, th.Background, "Yaw:"}, 67, 19, 6, th.Value, th.Background, "?°"}
+
+	fields[fHome] = field{label{33, 20, th.Derived, th.Background, "Home Pos:"}, 43, 20, 18, th.Value, th.Background, "?"}
+	fields[fHomeKey] = field{label{5, 20, th.Label, th.Background, "<Home> Key:"}, 16, 20, 9, th.Value, th.Background, "?"}
+
+	fields[fTimelapse] = field{label{5, 21, th.Label, th.Background, "Timelapse:"}, 16, 21, 8, th.Value, th.Background, "Off"}
+	fields[fManeuver] = field{label{30, 21, th.Label, th.Background, ""}, 30, 21, 40, th.Notice | termbox.AttrBold, th.Background, ""}
+
+	fields[fSSID] = field{label{10, 22, th.Label, th.Background, "SSID:"}, 16, 22, 20, th.Value, th.Background, "?"}
+	fields[fVersion] = field{label{57, 22, th.Label, th.Background, "Firmware:"}, 67, 22, 10, th.Value, th.Background, "?"}
+
+	fields[fMessage] = field{label{0, 23, th.Label, th.Background, ""}, 0, 23, minWidth - 1, th.Caution | termbox.AttrBold, th.Background, ""}
+
+	fields[fBounce] = field{label{35, 8, th.Label, th.Background, "Bounce:"}, 43, 8, 3, th.Value, th.Background, "Off"}
+	fields[fPeakHeight] = field{label{22, 2, th.Derived, th.Background, ""}, 22, 2, 10, th.Derived, th.Background, ""}
+	fields[fPeakSpeed] = field{label{55, 8, th.Derived, th.Background, "Peak Speed:"}, 67, 8, 7, th.Value, th.Background, "?m/s"}
+	fields[fFlightState] = field{label{9, 1, th.Derived, th.Background, "State:"}, 16, 1, 10, th.Value, th.Background, "?"}
+
+	hidden[fToggleKey] = toggleKey == 0 // only shown if configured
+	hidden[fChecklist] = !*checklistFlag
 
 }
 
 var (
-	drone       tello.Tello
-	fdLogging   bool
-	fdLog       *csv.Writer
-	wideVideo   bool
-	useJoystick bool
-	stickChan   chan<- tello.StickMessage
+	drone        telloDrone   // a *tello.Tello unless -sim is used
+	fdLog        flightLogger // nil unless -fdlog was given
+	wideVideo    bool
+	useJoystick  bool
+	stickChan    chan<- tello.StickMessage
+	prevFd       tello.FlightData // the previous update, for detecting changes of state
+	lastFdTime   time.Time        // when the flight data last changed
+	fastMode     bool             // the Tello always starts up in slow mode
+	lastHoverKey time.Time
+	lastBounce   time.Time
+	bounceOn     bool       // the drone has no way to tell us
+	flashUntil   time.Time  // the title is highlighted until then
+	posZero      [3]float32 // subtracted from the displayed MVO position
 )
 
 // program flags
 var (
-	cpuprofile  = flag.String("cpuprofile", "", "Write cpu profile to `file`")
-	fdLogFlag   = flag.String("fdlog", "", "Log some CSV flight data to this file")
-	joyHelpFlag = flag.Bool("joyhelp", false, "Print help for joystick control mapping and exit")
-	jsIDFlag    = flag.Int("jsid", 999, "ID number of joystick to use (see -jslist to get IDs)")
-	jsListFlag  = flag.Bool("jslist", false, "List attached joysticks")
-	jsTest      = flag.Bool("jstest", false, "Debug joystick mapping")
-	jsTypeFlag  = flag.String("jstype", "", "Type of joystick, options are DualShock4, HotasX")
-	keyHelpFlag = flag.Bool("keyhelp", false, "Print help for keyboard control mapping and exit")
-	x11Flag     = flag.Bool("x11", false, "Use '-vo x11' flag in case mplayer takes over entire window")
+	battIDFlag       = flag.String("battid", "", "Name of the battery in use, to keep a count of its flights")
+	checklistFlag    = flag.Bool("checklist", false, "Refuse to take off unless the battery, WiFi and temperature are fine, taking off again within 3s overrides")
+	cpuprofile       = flag.String("cpuprofile", "", "Write cpu profile to `file`")
+	diagonalFlag     = flag.Bool("diagonal", false, "Arrow keys set the direction of travel, two pressed together fly diagonally")
+	fdLogFlag        = flag.String("fdlog", "", "Log some flight data to this `file` (CSV, or JSON lines if it ends in .json)")
+	fdLogFmtFlag     = flag.String("fdlogfmt", "", "Flight log `format`, csv or json (default: from the -fdlog file extension)")
+	fieldsFlag       = flag.String("fields", "", "Show only these comma-separated `fields` in a compact layout, e.g. height,battery,derivedspeed,yaw")
+	flashFlag        = flag.Bool("flash", false, "Briefly highlight the title bar when a command is sent")
+	goHomeKeyFlag    = flag.String("gohomekey", "", "Use this `key` to fly home")
+	grpcFlag         = flag.String("grpc", "", "Serve gRPC control and telemetry on this `address`, e.g. :50051 (needs -tags grpc build)")
+	headlessFlag     = flag.Bool("headless", false, "Run without the terminal UI, reading commands from -script or stdin")
+	homeKeyFlag      = flag.String("homekey", "both", "What the <Home> key does, `both` (set home if unset, else fly home), set, go or none")
+	idleHoverFlag    = flag.Duration("idlehover", 0, "Hover if there is no keyboard or joystick input for this long while flying, e.g. 5s (default off)")
+	ifaceFlag        = flag.String("iface", "", "Network `interface` expected to reach the Tello, checked before connecting")
+	ifListFlag       = flag.Bool("iflist", false, "List network interfaces and their addresses")
+	interferenceFlag = flag.Int("interference", 60, "WiFi interference `percentage` above which to warn of a degraded link, 0 to disable")
+	joyHelpFlag      = flag.Bool("joyhelp", false, "Print help for joystick control mapping and exit")
+	jsBackendFlag    = flag.String("jsbackend", "native", "Joystick `backend`, native or sdl (SDL2 game controller mappings, needs a build with -tags sdl)")
+	jsCalFlag        = flag.Bool("jscal", false, "Calibrate the joystick centre at startup (leave sticks untouched)")
+	jsCheckFlag      = flag.Bool("jscheck", true, "Check at startup that the centred sticks read near zero, to catch a wrong -jstype (-jscheck=false skips it)")
+	jsConfigFlag     = flag.String("jsconfig", "", "Load joystick axis/button mappings from this JSON `file`")
+	jsIDFlag         = flag.String("jsid", "", "ID number of joystick to use, or a comma-separated list to combine several (see -jslist to get IDs)")
+	jsListFlag       = flag.Bool("jslist", false, "List attached joysticks")
+	jsLogFlag        = flag.String("jslog", "", "Log joystick stick values sent to the drone as CSV to this `file`")
+	jsMaxFlag        = flag.Int("jsmax", 32, "Number of joystick IDs, from 0, that -jslist looks at")
+	jsSmoothFlag     = flag.Float64("jssmooth", 0, "Joystick smoothing factor from 0 (off) to 0.99 (very smooth)")
+	jsTest           = flag.Bool("jstest", false, "Debug joystick mapping")
+	jsTypeFlag       = flag.String("jstype", "", "Type of joystick, options are DualShock4, HotasX, SwitchPro, Generic")
+	jsWizardFlag     = flag.Bool("jswizard", false, "Find the axes and buttons of the -jsid joystick(s) interactively and write a -jsconfig file")
+	keyHelpFlag      = flag.Bool("keyhelp", false, "Print help for keyboard control mapping and exit")
+	kmlFlag          = flag.String("kml", "", "Save the flight path to this KML `file` on exit, for Google Earth")
+	kmlHomeFlag      = flag.String("kmlhome", "", "Latitude,longitude of the takeoff point for -kml, e.g. 51.4779,-0.0015")
+	landOnQuitFlag   = flag.Bool("landonquit", false, "Land automatically without asking if quitting while flying")
+	logDirFlag       = flag.String("logdir", "", "Keep the flight log, KML, pictures and a session.json for each run in a new timestamped subdirectory of this `dir`")
+	logFileFlag      = flag.String("logfile", "", "Append telloterm's own messages to this `file` rather than showing them on stderr")
+	logTimeFlag      = flag.String("logtime", "clock", "Time `format` for the flight, joystick and text logs, clock (15:04:05.000), rfc3339 or epoch (Unix milliseconds)")
+	maxStickFlag     = flag.Int("maxstick", 100, "Limit joystick authority to this `percentage` of full deflection")
+	monoFlag         = flag.Bool("mono", false, "Use no colours at all, only bold and reverse video (overrides -theme)")
+	noBlinkFlag      = flag.Bool("noblink", false, "Do not flash critical status fields")
+	oneLineFlag      = flag.Bool("oneline", false, "Instead of the full display show a single updating status line on stdout, taking commands as for -headless")
+	onStartFlag      = flag.String("onstart", "", "Run the commands in this `file` once connected, as for -script, carrying on past any that fail")
+	picDirFlag       = flag.String("picdir", "", "Save pictures in this `dir` on exit, instead of the current directory (or the -logdir session)")
+	quietFlag        = flag.Bool("quiet", false, "Only log errors")
+	rawLogFlag       = flag.String("rawlog", "", "Append every decoded flight data update as JSON to this `file` for debugging")
+	readOnlyFlag     = flag.Bool("readonly", false, "Only watch the flight data, no commands that would change what the drone is doing are sent")
+	rpcSockFlag      = flag.String("rpcsock", "", "Accept JSON-RPC commands on this Unix socket `path`, e.g. /tmp/telloterm.sock")
+	rthBattFlag      = flag.Int("rthbatt", 0, "Fly home automatically when the battery falls to this `percentage` (0 = never)")
+	scriptFlag       = flag.String("script", "", "Run the commands in this `file` (with -headless)")
+	selfTestFlag     = flag.Bool("selftest", false, "Without connecting, cycle made-up values through every field to check the display, then exit (any key stops it early)")
+	setHomeKeyFlag   = flag.String("sethomekey", "", "Use this `key` to set (or reset) home at the current position")
+	shmFlag          = flag.String("shm", "", "Publish the latest flight data in this memory-mapped `file` (e.g. /dev/shm/telloterm), see shm.go for the layout")
+	simFlag          = flag.Bool("sim", false, "Fly a simulated drone instead of a real Tello (for testing)")
+	slewRateFlag     = flag.Int("slewrate", 0, "Let joystick values move away from centre by at most this `percentage` of full travel per update, 0 for no limit")
+	stickModeFlag    = flag.Int("stickmode", 2, "Joystick `mode` 1-4, as on RC transmitters (2: throttle and yaw on the left stick)")
+	takeOffHtFlag    = flag.Float64("takeoffheight", 0, "After each takeoff climb or descend to this height in `metres` and hover, 0 to leave it to the drone")
+	textOutFlag      = flag.String("textout", "", "Append a readable line of telemetry per update to this `file` or named pipe")
+	themeFlag        = flag.String("theme", "default", "Colour `theme`, one of default, mono or high-contrast, or a JSON theme file")
+	throttleBandFlag = flag.Int("throttleband", 0, "Hold altitude while the throttle stick is within this `percentage` of centre")
+	timelapseFlag    = flag.Int("timelapse", 0, "Take a picture every `seconds` (starts immediately, 'i' toggles)")
+	toggleKeyFlag    = flag.String("togglekey", "", "Use this `key` to take off when landed and land when flying")
+	triggersFlag     = flag.String("triggers", "off", "Analog L2/R2 triggers descend/climb, `mode` off, add (to the left stick) or override (the left stick while pressed)")
+	ttsFlag          = flag.Bool("tts", false, "Announce battery and altitude warnings via espeak (or say on macOS)")
+	udpOutFlag       = flag.String("udpout", "", "Send JSON telemetry packets to this UDP `host:port`")
+	verboseFlag      = flag.Bool("verbose", false, "Log debug messages too, e.g. every command run")
+	videoFileFlag    = flag.String("videofile", "", "Record the raw H.264 video to this `file` while the video is on, -fdlog then gets a VideoFrame column to line them up")
+	videoFPSFlag     = flag.Int("videofps", 60, "Frame rate mplayer is told to expect from the video")
+	videoStdoutFlag  = flag.Bool("videostdout", false, "Write the raw H.264 video to standard output instead of showing it with mplayer, e.g. telloterm -videostdout | ffplay -")
+	x11Flag          = flag.Bool("x11", false, "Use '-vo x11' flag in case mplayer takes over entire window")
 )
 
 func main() {
+	loadConfig()
 	flag.Parse()
+	if err := setupLogging(); err != nil {
+		log.Fatalf("Cannot set up logging - %v", err)
+	}
+	if *oneLineFlag {
+		if *videoStdoutFlag {
+			log.Fatalln("-oneline and -videostdout cannot both use stdout")
+		}
+		*headlessFlag = true // the status line replaces the full display
+	}
+	if *simFlag {
+		drone = newSimDrone()
+	} else {
+		drone = new(tello.Tello)
+	}
+	if *readOnlyFlag {
+		drone = readOnlyDrone{drone}
+	}
+	if *monoFlag {
+		*themeFlag = "mono"
+	}
+	if err := loadTheme(*themeFlag); err != nil {
+		log.Fatalf("Cannot load theme %s - %v\n", *themeFlag, err)
+	}
+	setupToggleKey()
+	setupHomeKeys()
 	if *keyHelpFlag {
 		printKeyHelp()
 		os.Exit(0)
 	}
+	if *videoFPSFlag < 1 {
+		log.Fatalln("The -videofps rate must be at least 1")
+	}
+	if *ifListFlag {
+		listInterfaces()
+		os.Exit(0)
+	}
+	if *ifaceFlag != "" && !*simFlag {
+		if err := checkInterface(*ifaceFlag); err != nil {
+			log.Fatalf("Cannot use -iface %s - %v\n", *ifaceFlag, err)
+		}
+	}
 	if *joyHelpFlag {
 		printJoystickHelp()
 		os.Exit(0)
@@ -208,12 +384,23 @@ func main() {
 		listJoysticks()
 		os.Exit(0)
 	}
-	if *jsIDFlag != 999 {
+	if *jsWizardFlag {
+		if *jsIDFlag == "" {
+			log.Fatalln("Please give the joystick(s) to set up with -jsid")
+		}
+		runJoystickWizard(*jsIDFlag)
+		os.Exit(0)
+	}
+	if *jsIDFlag != "" {
 		useJoystick = setupJoystick(*jsIDFlag)
 	}
 	if *jsTest {
 		readJoystick(true)
 	}
+	if *selfTestFlag {
+		runSelfTest()
+		return
+	}
 	if *cpuprofile != "" {
 		f, err := os.Create(*cpuprofile)
 		if err != nil {
@@ -222,41 +409,94 @@ func main() {
 		if err := pprof.StartCPUProfile(f); err != nil {
 			log.Fatal("could not start CPU profile: ", err)
 		}
-		defer pprof.StopCPUProfile()
+	}
+	if *logDirFlag != "" {
+		if err := setupLogDir(*logDirFlag); err != nil {
+			log.Fatal("Cannot create session log directory: ", err)
+		}
+	}
+	if err := checkLogTime(); err != nil {
+		log.Fatal(err)
 	}
 	if *fdLogFlag != "" {
-		fdlogFile, err := os.Create(*fdLogFlag)
+		var err error
+		fdLog, err = newFlightLogger(*fdLogFlag, *fdLogFmtFlag)
 		if err != nil {
 			log.Fatal("Cannot create Flight Log file: ", err)
 		}
-		defer fdlogFile.Close()
-		fdLog = csv.NewWriter(fdlogFile)
-		defer fdLog.Flush()
-		headers := []string{"Time", "X", "Y", "Z", "Yaw", "FDHeight"}
-		err = fdLog.Write(headers)
-		if err != nil {
+		if err = fdLog.WriteHeader(); err != nil {
 			log.Fatal("Cannot write headers to Flight Log file: ", err)
 		}
-		fdLogging = true
 	}
 
-	err := termbox.Init()
-	if err != nil {
-		panic(err)
+	if *rawLogFlag != "" {
+		startRawLog(*rawLogFlag)
+	}
+
+	if *textOutFlag != "" {
+		startTextOut(*textOutFlag)
+	}
+
+	if *jsLogFlag != "" {
+		startJSLog(*jsLogFlag)
+	}
+
+	if *ttsFlag {
+		if err := startTTS(); err != nil {
+			log.Fatal("Cannot start text to speech: ", err)
+		}
 	}
-	defer termbox.Close()
 
-	checkTermSize()
 	setupFields()
-	displayStaticFields()
+	if *fieldsFlag != "" {
+		packFields(*fieldsFlag)
+	}
+	if *battIDFlag != "" {
+		if *headlessFlag {
+			logInfo("%s", battSummary())
+		} else {
+			showMessage(battSummary())
+		}
+	}
+	if jsWarning != "" && !*headlessFlag {
+		showMessage(jsWarning)
+	}
+	defer reportPics() // after the display has gone, see savePics
+	if !*headlessFlag {
+		err := termbox.Init()
+		if err != nil {
+			panic(err)
+		}
+		defer termbox.Close()
 
-	displayDataFields() // FIXME remove: testing
+		checkTermSize()
+		displayStaticFields()
+	}
 
-	err = drone.ControlConnectDefault()
+	err := drone.ControlConnectDefault()
 	if err != nil {
 		termbox.Close()
 		log.Fatalf("Could not connect to Tello - %v", err)
 	}
+	logInfo("Connecting to Tello")
+
+	if *udpOutFlag != "" {
+		startUDPOut(*udpOutFlag)
+	}
+	handleSignals()
+	if *rpcSockFlag != "" {
+		startRPCSocket(*rpcSockFlag)
+	}
+	if *shmFlag != "" {
+		if err := startShm(*shmFlag); err != nil {
+			termbox.Close()
+			log.Fatalf("Cannot use -shm %s - %v", *shmFlag, err)
+		}
+	}
+	if err := setupKML(); err != nil {
+		termbox.Close()
+		log.Fatalf("Bad -kmlhome - %v", err)
+	}
 
 	// subscribe to FlightData events and ask for regular updates
 	fdChan, _ := drone.StreamFlightData(false, updatePeriodMs)
@@ -266,73 +506,191 @@ func main() {
 			fieldsMu.Lock()
 			updateFields(tmpFD)
 			fieldsMu.Unlock()
+			if udpOutChan != nil {
+				sendUDPOut(tmpFD)
+			}
+			if *kmlFlag != "" {
+				addKMLPoint(tmpFD)
+			}
+			if *textOutFlag != "" {
+				writeTextOut()
+			}
+			if *shmFlag != "" {
+				writeShm(tmpFD)
+			}
 		}
 	}()
 
 	// update data field display regularly
-	go func() {
-		for {
-			displayDataFields()
-			time.Sleep(updatePeriodMs * time.Millisecond)
-		}
-	}()
+	if *oneLineFlag {
+		startOneLine()
+	}
+	if !*headlessFlag {
+		go func() {
+			for {
+				updateLinkStatus()
+				if !termTooSmall() {
+					displayDataFields()
+				}
+				time.Sleep(updatePeriodMs * time.Millisecond)
+			}
+		}()
+	}
 
 	// ask for drone data not normally sent
 	drone.GetLowBatteryThreshold()
 	drone.GetMaxHeight()
-	drone.GetSSID()
-	drone.GetVersion()
+	go getDroneInfo()
+
+	if *grpcFlag != "" {
+		startGRPC(*grpcFlag)
+	}
+
+	if *timelapseFlag > 0 {
+		startTimelapse(time.Duration(*timelapseFlag) * time.Second)
+	}
+
+	if *videoStdoutFlag {
+		startVideo()
+	}
 
 	if useJoystick {
 		stickChan, _ = drone.StartStickListener()
 		go readJoystick(false)
 	}
 
-mainloop:
+	if *onStartFlag != "" {
+		runStartCommands(*onStartFlag)
+	}
+
+	if *headlessFlag {
+		runHeadless()
+	} else {
+		offerSavedHome()
+		if *idleHoverFlag > 0 {
+			startIdleWatchdog(*idleHoverFlag)
+		}
+		keyboardLoop()
+	}
+	shutdown()
+}
+
+var shutdownOnce sync.Once
+
+// shutdown saves everything worth keeping before the program exits,
+// it is safe to call more than once
+func shutdown() {
+	shutdownOnce.Do(tidyUp)
+}
+
+func tidyUp() {
+	if err := saveHome(); err != nil {
+		logError("Could not save home position - %v", err)
+	}
+	if *rpcSockFlag != "" {
+		os.Remove(*rpcSockFlag)
+	}
+	if *kmlFlag != "" {
+		if err := writeKML(*kmlFlag); err != nil {
+			logError("Could not write KML file %s - %v", *kmlFlag, err)
+		}
+	}
+
+	stopTimelapse()
+	savePics()
+
+	if fdLog != nil {
+		fdLog.Close()
+	}
+	if *rawLogFlag != "" {
+		stopRawLog()
+	}
+	if *textOutFlag != "" {
+		stopTextOut()
+	}
+	stopOneLine()
+	stopShm()
+	if *jsLogFlag != "" {
+		stopJSLog()
+	}
+	if err := writeSessionInfo(); err != nil {
+		logError("Could not write session info - %v", err)
+	}
+	pprof.StopCPUProfile() // harmless if not profiling
+}
+
+// keyboardLoop handles key presses until the user quits
+func keyboardLoop() {
 	for {
 		switch ev := termbox.PollEvent(); ev.Type {
 		case termbox.EventKey:
+			noteInput()
+			cancelTakeOffHeight()
+			wasHolding := cancelPosHold()
 			switch ev.Key {
-			case termbox.KeyEsc:
-				break mainloop
+			case termbox.KeyEsc, termbox.KeyCtrlC:
+				if confirmQuit() {
+					return
+				}
 			case termbox.KeyCtrlL:
-				termbox.Sync()
-				displayStaticFields()
-				displayDataFields()
+				redrawScreen()
+			case termbox.KeyTab:
+				nextPage()
+			case termbox.KeyPgup:
+				scrollDebug(-debugRows)
+			case termbox.KeyPgdn:
+				scrollDebug(debugRows)
 			case termbox.KeySpace:
-				drone.Hover()
-			case termbox.KeyArrowUp:
-				drone.Forward(keyPct)
-			case termbox.KeyArrowDown:
-				drone.Backward(keyPct)
-			case termbox.KeyArrowLeft:
-				drone.Left(keyPct)
-			case termbox.KeyArrowRight:
-				drone.Right(keyPct)
-			case termbox.KeyHome:
-				if drone.IsHomeSet() {
-					drone.AutoFlyToXY(0, 0)
-				} else {
-					drone.SetHome()
+				// ignore auto-repeat and frantic tapping
+				if time.Since(lastHoverKey) > hoverDebounce {
+					drone.Hover()
+					lastHoverKey = time.Now()
+					flashBanner()
 				}
+			case termbox.KeyBackspace, termbox.KeyBackspace2:
+				panicHover()
+				flashBanner()
+			case termbox.KeyArrowUp, termbox.KeyArrowDown, termbox.KeyArrowLeft, termbox.KeyArrowRight:
+				arrowKey(ev.Key)
+			case termbox.KeyHome:
+				homeKey()
 			default:
+				if toggleKey != 0 && ev.Ch == toggleKey { // takes precedence over the usual binding
+					launchOrLand()
+					flashBanner()
+					continue
+				}
+				if setHomeKey != 0 && ev.Ch == setHomeKey {
+					setHome()
+					flashBanner()
+					continue
+				}
+				if goHomeKey != 0 && ev.Ch == goHomeKey {
+					flyHomeKey()
+					continue
+				}
 				switch ev.Ch {
 				case 'q':
-					break mainloop
+					if confirmQuit() {
+						return
+					}
 				case 'r':
-					termbox.Sync()
-					displayStaticFields()
-					displayDataFields()
+					redrawScreen()
 				case 'b':
-					drone.Bounce()
+					toggleBounce()
+					flashBanner()
 				case 't':
-					drone.TakeOff()
+					takeOff()
+					flashBanner()
 				case 'o':
-					drone.ThrowTakeOff()
+					throwTakeOff()
+					flashBanner()
 				case 'l':
-					drone.Land()
+					land()
+					flashBanner()
 				case 'p':
-					drone.PalmLand()
+					palmLand()
+					flashBanner()
 				case 'w':
 					drone.Up(keyPct * 2)
 				case 'a':
@@ -343,22 +701,62 @@ mainloop:
 					drone.TurnRight(keyPct * 2)
 				case 'f':
 					drone.TakePicture()
+					flashBanner()
 				case 'v':
-					startVideo()
+					toggleVideo()
 				case '0':
-					drone.StartSmartVideo(tello.Sv360)
+					startSmartVideo(tello.Sv360)
+					flashBanner()
 				case '1':
 					drone.ForwardFlip()
+					flashBanner()
 				case '2':
 					drone.BackFlip()
+					flashBanner()
 				case '3':
 					drone.LeftFlip()
+					flashBanner()
 				case '4':
 					drone.RightFlip()
+					flashBanner()
 				case '+':
-					drone.SetFastMode()
+					setFastMode(true)
 				case '-':
-					drone.SetSlowMode()
+					setFastMode(false)
+				case ':':
+					runConsole()
+				case 'c':
+					cancelLowBattRTH()
+					cancelReturnAndLand()
+				case 'h':
+					if err := startReturnAndLand(); err != nil {
+						showMessage(fmt.Sprintf("Cannot return and land - %v", err))
+					} else {
+						flashBanner()
+					}
+				case 'i':
+					toggleTimelapse()
+				case 'z':
+					fieldsMu.Lock()
+					odo.reset()
+					fieldsMu.Unlock()
+				case 'x':
+					fieldsMu.Lock()
+					peak.reset()
+					fieldsMu.Unlock()
+				case 'Z':
+					zeroPosition()
+				case 'm':
+					resetMapScale()
+				case 'k':
+					if wasHolding { // k toggles, the hold has already been stopped
+						break
+					}
+					if err := startPosHold(); err != nil {
+						showMessage(fmt.Sprintf("Cannot hold position - %v", err))
+					} else {
+						flashBanner()
+					}
 				case '=':
 					if wideVideo {
 						drone.SetVideoNormal()
@@ -368,13 +766,12 @@ mainloop:
 					wideVideo = !wideVideo
 				}
 			}
-
+		case termbox.EventResize:
+			displayStaticFields()
+		case termbox.EventInterrupt: // from the signal handler
+			return
 		}
 	}
-
-	if drone.NumPics() > 0 {
-		drone.SaveAllPics(fmt.Sprintf("tello_pic_%s", time.Now().Format(time.RFC3339)))
-	}
 }
 
 func printKeyHelp() {
@@ -382,10 +779,15 @@ func printKeyHelp() {
 		`TelloTerm Keyboard Control Mapping
 
 <Cursor Keys> Move Left/Right/Forward/Backward
+              with -diagonal each sets the direction of travel, and two
+              pressed within 0.3s (e.g. Up then Right) fly diagonally
 w|a|s|d       W: Up, S: Down, A: Turn Left, D: Turn Right
 <SPACE>       Hover (stop all movement)
-<HOME>        Set Home position or fly to Home position
+<BACKSPACE>   Panic stop - centre the sticks and hover at once
+<HOME>        Set Home position, or fly to it if already set (see -homekey)
 b             Bounce (toggle)
+c             Cancel low-battery return home, or return and land
+h             Return home and land there
 t             Takeoff
 o             Throw Takeoff
 l             Land
@@ -393,34 +795,84 @@ p             Palm Land
 0             360 degree smart video flight
 1|2|3|4       Flip Fwd/Back/Left/Right
 f             Take Picture (Foto)
+i             Start/Stop Timelapse pictures
 q/<Escape>    Quit
 r/<Ctrl-L>	  Refresh Screen
-v             Start Video (mplayer) Window
+<Tab>         Switch between cockpit, position map and raw data pages
+<PgUp/PgDn>   Scroll the raw data page
+v             Start/Stop Video (mplayer window or -videostdout)
 -             Slow (normal) flight mode
 +             Fast (sports) flight mode
 =             Switch between normal and wide video mode
+z             Zero the distance odometer
+x             Reset the peak height and speed (also reset at takeoff)
+:             Type a command, e.g. "flyto 1 0" or "lowbatt 25" ("help" lists them)
+Z             Make the current spot the origin of the displayed position
+m             Rescale the position map to fit just home and the drone
+k             Hold position (experimental), steering back against drift
 `)
+	if toggleKey != 0 {
+		fmt.Printf("%c             Takeoff if on the ground, Land if flying\n", toggleKey)
+	}
+	if setHomeKey != 0 {
+		fmt.Printf("%c             Set Home position\n", setHomeKey)
+	}
+	if goHomeKey != 0 {
+		fmt.Printf("%c             Fly to Home position\n", goHomeKey)
+	}
 }
 
 func tbprint(x, y int, fg, bg termbox.Attribute, msg string) {
 	for _, c := range msg {
-		termbox.SetCell(x, y, c, fg, bg)
+		setCell(x, y, c, fg, bg)
 		x += runewidth.RuneWidth(c)
 	}
 }
 
 func checkTermSize() (w, h int) {
 	w, h = termbox.Size()
-	if w < minWidth || h < minHeight {
+	if w < reqWidth || h < reqHeight {
 		termbox.Close()
-		log.Fatalln("Please resize terminal window to at least 24x80 and restart program.")
+		log.Fatalf("Please resize terminal window to at least %dx%d and restart program.\n", reqWidth, reqHeight)
 	}
 	return w, h
 }
 
+// termTooSmall reports whether the terminal has been shrunk below the
+// required size since startup, the display is suspended until it grows back
+func termTooSmall() bool {
+	w, h := termbox.Size()
+	return w < reqWidth || h < reqHeight
+}
+
+// redrawScreen repaints everything, e.g. after another program has scribbled on the terminal
+func redrawScreen() {
+	termbox.Sync()
+	displayStaticFields()
+	if !termTooSmall() {
+		displayDataFields()
+	}
+}
+
 func displayStaticFields() {
-	termbox.Clear(termbox.ColorDefault, termbox.ColorDefault)
-	for _, l := range staticLabels {
+	termbox.Clear(th.Value, th.Background)
+	staticMu.Lock()
+	drawnStatic = [fNumFields]string{} // everything must be redrawn
+	staticMu.Unlock()
+	if termTooSmall() {
+		w, h := termbox.Size()
+		msg := fmt.Sprintf("Please enlarge terminal to %dx%d", reqWidth, reqHeight)
+		tbprint((w-len(msg))/2, h/2, th.Caution|termbox.AttrBold, th.Background, msg)
+		termbox.Flush()
+		return
+	}
+	fieldsMu.RLock()
+	onCockpit := page == pageCockpit
+	fieldsMu.RUnlock()
+	for i, l := range staticLabels {
+		if i > 0 && !onCockpit { // only the title on other pages
+			break
+		}
 		tbprint(l.x, l.y, l.fg, l.bg, l.text)
 	}
 	termbox.Flush()
@@ -428,19 +880,215 @@ func displayStaticFields() {
 
 func displayDataFields() {
 	fieldsMu.RLock()
-	for _, d := range fields {
-		tbprint(d.lab.x, d.lab.y, d.lab.fg, d.lab.bg, d.lab.text)
-		tbprint(d.x, d.y, d.fg, d.bg, padString(d.value, d.w))
+	defer fieldsMu.RUnlock()
+	// the banner lights up while the drone reports that it is hovering
+	banner := staticLabels[0]
+	if prevFd.DroneHover {
+		banner.fg = th.Good | termbox.AttrReverse | termbox.AttrBold
+		banner.text = "  HOVER  " // same width as the title
+	}
+	if time.Now().Before(flashUntil) {
+		banner.fg = th.Notice | termbox.AttrReverse | termbox.AttrBold
+	}
+	tbprint(banner.x, banner.y, banner.fg, banner.bg, banner.text)
+	tagX := 0
+	if compact { // the title has moved to the left, so follow it
+		tagX = staticLabels[0].x + len(staticLabels[0].text) + 1
+	}
+	if *simFlag {
+		tbprint(tagX, 0, th.Special|termbox.AttrReverse|termbox.AttrBold, th.Background, " SIMULATOR ")
+		tagX += 12
+	}
+	if *readOnlyFlag {
+		tbprint(tagX, 0, th.Bad|termbox.AttrReverse|termbox.AttrBold, th.Background, " READ ONLY ")
+	}
+	switch page {
+	case pageMap:
+		w, h := termbox.Size()
+		drawMap(0, 1, w, h-2)
+		m := fields[fMessage]
+		tbprint(m.x, m.y, m.fg, m.bg, padString(m.value, m.w))
+	case pageDebug:
+		displayDebugPage()
+		m := fields[fMessage]
+		tbprint(m.x, m.y, m.fg, m.bg, padString(m.value, m.w))
+	default:
+		displayCockpit()
 	}
-	fieldsMu.RUnlock()
 	termbox.Flush()
 }
 
+// displayCockpit draws the main page, fieldsMu must be read-locked
+func displayCockpit() {
+	blinkOn := (time.Now().UnixNano()/int64(blinkPeriod))%2 == 0
+	for i, d := range fields {
+		if hidden[i] {
+			continue
+		}
+		if nonVolatile[i] && !staticChanged(i, d.value) {
+			continue
+		}
+		fg := d.fg
+		if blinkOn && isBlinking(i) {
+			fg ^= termbox.AttrReverse
+		}
+		tbprint(d.lab.x, d.lab.y, d.lab.fg, d.lab.bg, d.lab.text)
+		tbprint(d.x, d.y, fg, d.bg, padString(d.value, d.w))
+	}
+	if !compact {
+		drawCompass()
+		drawVelArrow()
+		drawBattSpark()
+	}
+	// the map goes beside the cockpit if the terminal is wide enough
+	if w, h := termbox.Size(); w >= reqWidth+mapCornerW && h >= mapCornerH {
+		drawMap(w-mapCornerW, h-mapCornerH, mapCornerW, mapCornerH)
+	}
+}
+
+// staticChanged reports whether a nonVolatile field needs drawing and notes that it has been
+func staticChanged(i int, value string) bool {
+	staticMu.Lock()
+	defer staticMu.Unlock()
+	if drawnStatic[i] == value {
+		return false
+	}
+	drawnStatic[i] = value
+	return true
+}
+
+const (
+	infoRetries     = 10
+	infoRetryPeriod = 2 * time.Second
+)
+
+// getDroneInfo asks for the SSID and firmware version, repeating the
+// requests until the drone has answered both
+func getDroneInfo() {
+	for tries := 0; tries < infoRetries; tries++ {
+		fd := currentFd()
+		if fd.SSID != "" && fd.Version != "" {
+			return
+		}
+		if fd.SSID == "" {
+			drone.GetSSID()
+		}
+		if fd.Version == "" {
+			drone.GetVersion()
+		}
+		time.Sleep(infoRetryPeriod)
+	}
+}
+
+// isBlinking reports whether field i should be flashed this frame
+func isBlinking(i int) bool {
+	if *noBlinkFlag || !critical[i] {
+		return false
+	}
+	for _, b := range blinkFields {
+		if b == i {
+			return true
+		}
+	}
+	return false
+}
+
 func padString(unpadded string, l int) (padded string) {
 	format := "%-" + strconv.Itoa(l) + "v"
 	return fmt.Sprintf(format, unpadded)
 }
 
+// setFastMode switches the drone between fast (sports) and slow (normal) flight
+// and records the choice for display, it is shared by the keyboard and joystick
+func setFastMode(fast bool) {
+	if fast {
+		drone.SetFastMode()
+	} else {
+		drone.SetSlowMode()
+	}
+	fieldsMu.Lock()
+	fastMode = fast
+	if fastMode {
+		fields[fSpeedMode].value = "Fast"
+		fields[fSpeedMode].fg = th.Caution | termbox.AttrBold
+	} else {
+		fields[fSpeedMode].value = "Slow"
+		fields[fSpeedMode].fg = th.Value
+	}
+	fieldsMu.Unlock()
+}
+
+// panicHover brings the drone to a stable stop without cutting the motors,
+// unlike Space it is never debounced
+func panicHover() {
+	if stickChan != nil {
+		stickChan <- tello.StickMessage{}
+	}
+	drone.Hover()
+	showMessage("Panic stop - hovering")
+}
+
+// toggleBounce switches bounce mode, shared by the keyboard, joystick and scripts,
+// presses closer together than hoverDebounce are taken to be key repeats
+func toggleBounce() {
+	fieldsMu.Lock()
+	repeat := time.Since(lastBounce) < hoverDebounce
+	if !repeat {
+		lastBounce = time.Now()
+		bounceOn = !bounceOn
+		showBounce()
+	}
+	fieldsMu.Unlock()
+	if !repeat {
+		drone.Bounce()
+	}
+}
+
+// showBounce updates the Bounce field, fieldsMu must be held
+func showBounce() {
+	if bounceOn {
+		fields[fBounce].value = "On"
+		fields[fBounce].fg = th.Good | termbox.AttrBold
+	} else {
+		fields[fBounce].value = "Off"
+		fields[fBounce].fg = th.Value
+	}
+}
+
+// zeroPosition makes the current MVO position the origin of the position readout,
+// it does not affect home or the map
+func zeroPosition() {
+	fieldsMu.Lock()
+	posZero = [3]float32{prevFd.MVO.PositionX, prevFd.MVO.PositionY, prevFd.MVO.PositionZ}
+	fields[fPosZero].value = "(zeroed)"
+	fieldsMu.Unlock()
+	showMessage("Position readout zeroed here")
+}
+
+// flashBanner highlights the title for a couple of frames to show a command was sent, if -flash is set
+func flashBanner() {
+	if !*flashFlag {
+		return
+	}
+	fieldsMu.Lock()
+	flashUntil = time.Now().Add(flashTime)
+	fieldsMu.Unlock()
+}
+
+// showMessage displays msg on the bottom line of the screen, "" clears it
+func showMessage(msg string) {
+	fieldsMu.Lock()
+	fields[fMessage].value = msg
+	fieldsMu.Unlock()
+}
+
+// currentFd returns the most recent flight data received
+func currentFd() tello.FlightData {
+	fieldsMu.RLock()
+	defer fieldsMu.RUnlock()
+	return prevFd
+}
+
 func boolToYN(b bool) string {
 	if b {
 		return "Y"
@@ -451,22 +1099,41 @@ func boolToYN(b bool) string {
 func updateFields(newFd tello.FlightData) {
 	fields[fHeight].value = fmt.Sprintf("%.1fm", float32(newFd.Height)/10)
 	fields[fBattery].value = fmt.Sprintf("%d%%", newFd.BatteryPercentage)
+	fields[fBattery].fg = battColour(newFd.BatteryPercentage, newFd.LowBatteryThreshold)
+	addSparkSample(time.Now(), newFd.BatteryPercentage, fields[fBattery].fg)
 	fields[fWifiStrength].value = fmt.Sprintf("%d%%", newFd.WifiStrength)
 
 	fields[fMaxHeight].value = fmt.Sprintf("%dm", newFd.MaxHeight)
 	fields[fLowBattThresh].value = fmt.Sprintf("%d%%", newFd.LowBatteryThreshold)
 	fields[fWifiInterference].value = fmt.Sprintf("%d%%", newFd.WifiInterference)
+	// heavy interference causes control stutters, so warn before it gets worse
+	noisy := *interferenceFlag > 0 && int(newFd.WifiInterference) > *interferenceFlag
+	critical[fWifiInterference] = noisy
+	if noisy {
+		fields[fWifiInterference].fg = th.Bad | termbox.AttrBold
+		if int(prevFd.WifiInterference) <= *interferenceFlag && newFd.Flying {
+			fields[fMessage].value = fmt.Sprintf("WiFi interference %d%% - link degraded, consider landing", newFd.WifiInterference)
+		}
+	} else {
+		fields[fWifiInterference].fg = th.Value
+	}
 
-	fields[fDerivedSpeed].value = fmt.Sprintf("%.1fm/s", math.Sqrt(float64(newFd.NorthSpeed*newFd.NorthSpeed)+float64(newFd.EastSpeed*newFd.EastSpeed)))
+	derivedSpeed := math.Sqrt(float64(newFd.NorthSpeed*newFd.NorthSpeed) + float64(newFd.EastSpeed*newFd.EastSpeed))
+	fields[fDerivedSpeed].value = fmt.Sprintf("%.1fm/s", derivedSpeed)
+	if newFd.Flying && !prevFd.Flying {
+		peak.reset()
+	}
+	peak.update(float64(newFd.Height)/10, derivedSpeed)
+	fields[fPeakHeight].value = fmt.Sprintf("max %.1fm", peak.height)
+	fields[fPeakSpeed].value = fmt.Sprintf("%.1fm/s", peak.speed)
 	fields[fGroundSpeed].value = fmt.Sprintf("%dm/s", newFd.GroundSpeed)
 	fields[fFwdSpeed].value = fmt.Sprintf("%dm/s", newFd.NorthSpeed)
 	fields[fLatSpeed].value = fmt.Sprintf("%dm/s", newFd.EastSpeed)
 
 	fields[fVertSpeed].value = fmt.Sprintf("%dm/s", newFd.VerticalSpeed)
 
-	fields[fBattLow].value = boolToYN(newFd.BatteryLow)
-	fields[fBattCrit].value = boolToYN(newFd.BatteryCritical)
-	fields[fBattState].value = boolToYN(newFd.BatteryState)
+	fields[fBattStatus].value, fields[fBattStatus].fg = battStatus(newFd)
+	critical[fBattStatus] = newFd.BatteryCritical
 
 	fields[fGroundVis].value = boolToYN(newFd.DownVisualState)
 	fields[fErrorState].value = boolToYN(newFd.ErrorState)
@@ -474,91 +1141,371 @@ func updateFields(newFd tello.FlightData) {
 
 	fields[fOnGround].value = boolToYN(newFd.OnGround)
 	fields[fHovering].value = boolToYN(newFd.DroneHover)
+	if newFd.DroneHover {
+		fields[fHovering].fg = th.Good | termbox.AttrBold
+	} else {
+		fields[fHovering].fg = th.Value
+	}
 	fields[fFlying].value = boolToYN(newFd.Flying)
+	fields[fFlightState].value, fields[fFlightState].fg = flightState(newFd, time.Now())
+	checkTakeOffHeight(newFd, fields[fFlightState].value, time.Now())
+	showChecklist(newFd, time.Now())
 
-	fields[fFlyMode].value = fmt.Sprintf("%d", newFd.FlyMode)
+	fields[fFlyMode].value = modeName(flyModes, newFd.FlyMode)
 
-	fields[fCameraState].value = fmt.Sprintf("%d", newFd.CameraState)
+	fields[fCameraState].value = modeName(cameraStates, newFd.CameraState)
 	fields[fDroneFlyTimeLeft].value = fmt.Sprintf("%d", newFd.DroneFlyTimeLeft)
 	fields[fDroneBattLeft].value = fmt.Sprintf("%dmV", newFd.BatteryMilliVolts)
+	if newFd.BatteryMilliVolts > 0 {
+		cellV := float64(newFd.BatteryMilliVolts) / 1000 / telloCells
+		fields[fCellVolts].value = fmt.Sprintf("%.2fV", cellV)
+		health := battHealth(cellV, newFd.BatteryPercentage, newFd.Flying)
+		fields[fBattHealth].value = health
+		switch health {
+		case "Good":
+			fields[fBattHealth].fg = th.Good
+		case "Sag":
+			fields[fBattHealth].fg = th.Caution
+		default:
+			fields[fBattHealth].fg = th.Bad | termbox.AttrBold
+		}
+	}
+
+	// restart the battery trend at each takeoff
+	now := time.Now()
+	if newFd.Flying && !prevFd.Flying {
+		battEst.reset()
+		battTakeoff = now
+	}
+	updateManeuver(newFd, now)
+	if !newFd.Flying && prevFd.Flying {
+		go stopTimelapse() // we hold fieldsMu here
+		bounceOn = false   // landing ends bounce mode
+		showBounce()
+		if *battIDFlag != "" && !battTakeoff.IsZero() {
+			go recordBattFlight(now.Sub(battTakeoff))
+		}
+	}
+	battEst.addSample(now, newFd.BatteryPercentage)
+	if left, ok := battEst.estimate(now); ok {
+		fields[fBattETA].value = formatMinSec(left)
+	} else {
+		fields[fBattETA].value = "?"
+	}
 
 	fields[fVelX].value = fmt.Sprintf("%dcm/s", newFd.MVO.VelocityX)
 	fields[fVelY].value = fmt.Sprintf("%dcm/s", newFd.MVO.VelocityY)
 	fields[fVelZ].value = fmt.Sprintf("%dcm/s", newFd.MVO.VelocityZ)
 
-	fields[fPosX].value = fmt.Sprintf("%f", newFd.MVO.PositionX)
-	fields[fPosY].value = fmt.Sprintf("%f", newFd.MVO.PositionY)
-	fields[fPosZ].value = fmt.Sprintf("%f", newFd.MVO.PositionZ)
+	fields[fPosX].value = fmt.Sprintf("%f", newFd.MVO.PositionX-posZero[0])
+	fields[fPosY].value = fmt.Sprintf("%f", newFd.MVO.PositionY-posZero[1])
+	fields[fPosZ].value = fmt.Sprintf("%f", newFd.MVO.PositionZ-posZero[2])
+
+	// without ground visual tracking the MVO figures can't be trusted
+	mvoFg := th.Value
+	fields[fMVOStatus].value = ""
+	if !newFd.DownVisualState {
+		mvoFg = th.Dim
+		fields[fMVOStatus].value = "(tracking lost)"
+	}
+	for f := fVelX; f <= fPosZ; f++ {
+		fields[f].fg = mvoFg
+	}
+
+	odo.update(newFd.MVO.PositionX, newFd.MVO.PositionY, newFd.MVO.PositionZ)
+	recordTrail(newFd.MVO.PositionX, newFd.MVO.PositionY)
+	fields[fOdometer].value = fmt.Sprintf("%.1fm", odo.total)
 
 	fields[fQatW].value = fmt.Sprintf("%f", newFd.IMU.QuaternionW)
 	fields[fQatX].value = fmt.Sprintf("%f", newFd.IMU.QuaternionX)
 	fields[fQatY].value = fmt.Sprintf("%f", newFd.IMU.QuaternionY)
 	fields[fQatZ].value = fmt.Sprintf("%f", newFd.IMU.QuaternionZ)
 	fields[fTemp].value = fmt.Sprintf("%dC", newFd.IMU.Temperature)
+	critical[fTemp] = newFd.IMU.Temperature > maxTempC
 
-	// p, r, y := tello.QuatToEulerDeg(newFd.IMU.QuaternionX, newFd.IMU.QuaternionY, newFd.IMU.QuaternionZ, newFd.IMU.QuaternionW)
-	// fields[fRoll].value = fmt.Sprintf("%d", r)
-	// fields[fPitch].value = fmt.Sprintf("%d", p)
+	pitch, roll, _ := tello.QuatToEulerDeg(newFd.IMU.QuaternionX, newFd.IMU.QuaternionY, newFd.IMU.QuaternionZ, newFd.IMU.QuaternionW)
+	fields[fRoll].value = fmt.Sprintf("%d°", roll)
+	fields[fPitch].value = fmt.Sprintf("%d°", pitch)
 	fields[fYaw].value = fmt.Sprintf("%d°", newFd.IMU.Yaw)
 
-	if drone.IsHomeSet() {
-		fields[fHome].value = "Set"
-	} else {
-		fields[fHome].value = "Unset"
+	homeSet := drone.IsHomeSet()
+	homeMu.Lock()
+	fields[fHome].value = homeLabel(homeSet, home)
+	homeMu.Unlock()
+	fields[fHomeKey].value = homeKeyAction(homeSet)
+
+	// the zero FlightData we start with would read as an empty battery
+	if *ttsFlag && (!lastFdTime.IsZero() || newFd != prevFd) {
+		ttsCallouts(newFd)
+	}
+	if *rthBattFlag > 0 {
+		checkLowBattRTH(newFd, now)
+	}
+	checkReturnAndLand(newFd, now)
+	checkPosHold(newFd)
+	if toggleKey != 0 {
+		fields[fToggleKey].value = toggleKeyLabel(newFd)
+	}
+
+	// these never change, so keep the first real answers
+	if newFd.SSID != "" && fields[fSSID].value == "?" {
+		fields[fSSID].value = newFd.SSID
+	}
+	if newFd.Version != "" && fields[fVersion].value == "?" {
+		fields[fVersion].value = newFd.Version
+	}
+
+	if fdLog != nil {
+		fdLog.WriteRecord(newFd)
+	}
+	if *rawLogFlag != "" {
+		writeRawLog(now, newFd)
 	}
 
-	fields[fSSID].value = newFd.SSID
-	fields[fVersion].value = newFd.Version
+	// a live drone's IMU readings are never perfectly still, so unchanged
+	// data means that we are not hearing from it
+	if newFd != prevFd {
+		lastFdTime = now
+		noteDataChange(now)
+	}
+	prevFd = newFd
+}
 
-	if fdLogging {
-		logLine := []string{time.Now().Format("15:04:05.000"), fmt.Sprintf("%f", newFd.MVO.PositionX),
-			fmt.Sprintf("%f", newFd.MVO.PositionY), fmt.Sprintf("%f", newFd.MVO.PositionZ),
-			fmt.Sprintf("%d", newFd.IMU.Yaw), fmt.Sprintf("%.1f", float32(newFd.Height)/10)}
-		fdLog.Write(logLine)
+var (
+	videoMu      sync.Mutex
+	videoRunning bool               // true while mplayer is running or video goes to stdout
+	videoCancel  context.CancelFunc // stops the video goroutines and mplayer
+	videoDone    chan struct{}      // closed once the video has stopped and been tidied up
+)
+
+// toggleVideo starts the video window if it is not running, otherwise stops it
+func toggleVideo() {
+	videoMu.Lock()
+	running := videoRunning
+	videoMu.Unlock()
+	if running {
+		stopVideo()
+	} else {
+		startVideo()
 	}
 }
 
+// updateLinkStatus refreshes the connection indicators in the header
+func updateLinkStatus() {
+	videoMu.Lock()
+	videoOn := videoRunning
+	videoMu.Unlock()
+
+	fieldsMu.Lock()
+	defer fieldsMu.Unlock()
+	wasLink := fields[fLink].value
+	defer func() {
+		switch link := fields[fLink].value; {
+		case link == wasLink:
+		case link == "LOST":
+			logWarn("Link to Tello lost")
+		default:
+			logInfo("Link to Tello %s", link)
+		}
+	}()
+	switch {
+	case lastFdTime.IsZero():
+		fields[fLink].value = "CONNECTING"
+		fields[fLink].fg = th.Caution
+		critical[fLink] = false
+	case time.Since(lastFdTime) > linkTimeout:
+		fields[fLink].value = "LOST"
+		fields[fLink].fg = th.Bad | termbox.AttrBold
+		critical[fLink] = true
+	default:
+		fields[fLink].value = "CONNECTED"
+		fields[fLink].fg = th.Good
+		critical[fLink] = false
+	}
+	showLinkLag(time.Now())
+	if videoOn {
+		fields[fVideo].value = "ON"
+		fields[fVideo].fg = th.Good
+	} else {
+		fields[fVideo].value = "OFF"
+		fields[fVideo].fg = th.Bad
+	}
+}
+
+// videoTimeout is how long to wait for any video before assuming it is being blocked
+const videoTimeout = 5 * time.Second
+
 func startVideo() {
+	videoMu.Lock()
+	defer videoMu.Unlock()
+	if videoRunning { // only ever run one mplayer
+		return
+	}
+
 	videochan, err := drone.VideoConnectDefault()
+	if err == errSimNoVideo {
+		showMessage("No video in the simulator")
+		return
+	}
+	if err == errReadOnly {
+		showMessage("No video in read only mode")
+		return
+	}
 	if err != nil {
+		termbox.Close()
 		log.Fatalf("Tello VideoConnectDefault() failed with error %v", err)
 	}
 
-	// start external mplayer instance...
-	// the -vo X11 parm allows it to run nicely inside a virtual machine
-	// setting the FPS to 60 seems to produce smoother video
-	var player *exec.Cmd
-	if *x11Flag {
-		player = exec.Command("mplayer", "-nosound", "-vo", "x11", "-fps", "60", "-")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	var (
+		videoOut io.Writer
+		wait     func() // returns when the video output has finished
+	)
+	if *videoStdoutFlag {
+		// the display uses /dev/tty, so standard output is free for the video
+		videoOut, wait = os.Stdout, func() { <-ctx.Done() }
 	} else {
-		player = exec.Command("mplayer", "-nosound", "-fps", "60", "-")
-	}
+		// start external mplayer instance...
+		// the -vo X11 parm allows it to run nicely inside a virtual machine
+		// setting the FPS to 60 seems to produce smoother video, slow links may do better with less
+		fps := strconv.Itoa(*videoFPSFlag)
+		var player *exec.Cmd
+		if *x11Flag {
+			player = exec.CommandContext(ctx, "mplayer", "-nosound", "-vo", "x11", "-fps", fps, "-")
+		} else {
+			player = exec.CommandContext(ctx, "mplayer", "-nosound", "-fps", fps, "-")
+		}
 
-	playerIn, err := player.StdinPipe()
-	if err != nil {
-		log.Fatalf("Unable to get STDIN for mplayer %v", err)
+		playerIn, err := player.StdinPipe()
+		if err != nil {
+			log.Fatalf("Unable to get STDIN for mplayer %v", err)
+		}
+		if err := player.Start(); err != nil {
+			log.Fatalf("Unable to start mplayer - %v", err)
+			return
+		}
+		videoOut, wait = playerIn, func() { player.Wait() }
 	}
-	if err := player.Start(); err != nil {
-		log.Fatalf("Unable to start mplayer - %v", err)
-		return
+	if *videoFileFlag != "" {
+		if err := startVideoFile(*videoFileFlag); err != nil {
+			showMessage(fmt.Sprintf("Cannot record video - %v", err))
+		}
 	}
+	videoRunning, videoCancel, videoDone = true, cancel, done
+	logInfo("Video started")
+
+	// whether stopVideo() cancelled it, the user closed the mplayer window or
+	// the program reading stdout went away, everything else is then shut down
+	go func() {
+		wait()
+		cancel()
+		drone.VideoDisconnect()
+		stopVideoFile()
+		logInfo("Video stopped")
+		videoMu.Lock()
+		videoRunning = false
+		videoMu.Unlock()
+		close(done)
+	}()
 
-	// start video feed when drone connects
+	// start video feed when drone connects, if nothing at all turns up the
+	// re-requests are paused and the user is told, they resume if video appears
 	drone.GetVideoSpsPps()
+	gotVideo := make(chan struct{}, 1)
 	go func() {
+		lastVideo := time.Now()
+		stalled := false
+		tick := time.NewTicker(500 * time.Millisecond)
+		defer tick.Stop()
 		for {
-			drone.GetVideoSpsPps()
-			time.Sleep(500 * time.Millisecond)
+			select {
+			case <-ctx.Done():
+				return
+			case <-gotVideo:
+				lastVideo = time.Now()
+				if stalled {
+					stalled = false
+					showMessage("Video is now being received")
+					logInfo("Video is now being received")
+				}
+			case <-tick.C:
+				if stalled {
+					continue
+				}
+				if time.Since(lastVideo) > videoTimeout {
+					stalled = true
+					showMessage("No video received - check your firewall allows UDP port 6038 from the Tello")
+					logWarn("No video received after %v", videoTimeout)
+					continue
+				}
+				drone.GetVideoSpsPps()
+			}
 		}
 	}()
 
 	go func() {
+		// the measured frame rate is shown once a second
+		frames := 0
+		tick := time.NewTicker(time.Second)
+		defer func() {
+			tick.Stop()
+			fieldsMu.Lock()
+			fields[fVideoFPS].value = ""
+			fieldsMu.Unlock()
+		}()
 		for {
-			vbuf := <-videochan
-			_, err := playerIn.Write(vbuf)
-			if err != nil {
-				log.Fatalf("Error writing to mplayer %v\n", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-tick.C:
+				fieldsMu.Lock()
+				fields[fVideoFPS].value = fmt.Sprintf("%dfps", frames)
+				fieldsMu.Unlock()
+				frames = 0
+			case vbuf := <-videochan:
+				select {
+				case gotVideo <- struct{}{}:
+				default:
+				}
+				if isFrameStart(vbuf) {
+					frames++
+				}
+				writeVideoFile(vbuf)
+				if _, err := videoOut.Write(vbuf); err != nil {
+					// usually because mplayer or the reader of stdout has gone,
+					// cancelling makes sure of it
+					if *videoStdoutFlag {
+						showMessage(fmt.Sprintf("Video output stopped - %v", err))
+					}
+					logWarn("Video output stopped - %v", err)
+					cancel()
+					return
+				}
 			}
 		}
 	}()
 }
+
+// isFrameStart reports whether a video packet begins a new picture, i.e. an
+// H.264 NAL unit containing a coded slice rather than SPS/PPS etc.
+func isFrameStart(buf []byte) bool {
+	if len(buf) < 5 || buf[0] != 0 || buf[1] != 0 || buf[2] != 0 || buf[3] != 1 {
+		return false
+	}
+	nalType := buf[4] & 0x1f
+	return nalType == 1 || nalType == 5
+}
+
+// stopVideo closes mplayer and waits for the video to be shut down
+func stopVideo() {
+	videoMu.Lock()
+	if !videoRunning {
+		videoMu.Unlock()
+		return
+	}
+	videoCancel() // also kills mplayer
+	done := videoDone
+	videoMu.Unlock()
+	<-done
+}
@@ -0,0 +1,107 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The text output is a running, human-readable copy of the panel, one line per
+// flight data update, for watching with tail -f in another window.  It is not
+// meant to be parsed - use -fdlog or -rawlog for that.
+
+const textOutFlushPeriod = time.Second
+
+var (
+	textOutMu  sync.Mutex
+	textOutBuf *bufio.Writer
+	textOutF   *os.File
+)
+
+// startTextOut opens the text output in the background, as opening a named pipe
+// blocks until something reads from it
+func startTextOut(path string) {
+	go func() {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+		if err != nil {
+			logError("Cannot open text output %s - %v", path, err)
+			return
+		}
+		textOutMu.Lock()
+		textOutF = f
+		textOutBuf = bufio.NewWriter(f)
+		textOutMu.Unlock()
+		for range time.Tick(textOutFlushPeriod) {
+			textOutMu.Lock()
+			if textOutBuf == nil {
+				textOutMu.Unlock()
+				return
+			}
+			if err := textOutBuf.Flush(); err != nil {
+				logError("Text output stopped - %v", err)
+				textOutF.Close()
+				textOutBuf = nil
+			}
+			textOutMu.Unlock()
+		}
+	}()
+}
+
+// textOutLine formats the labelled panel fields as a single line, the caller
+// must hold fieldsMu
+func textOutLine(now time.Time) string {
+	parts := []string{logTime(now)}
+	for i, f := range fields {
+		if hidden[i] || f.lab.text == "" || f.value == "" {
+			continue
+		}
+		parts = append(parts, strings.TrimRight(f.lab.text, ": ")+": "+f.value)
+	}
+	return strings.Join(parts, " | ")
+}
+
+func writeTextOut() {
+	fieldsMu.RLock()
+	line := textOutLine(time.Now())
+	fieldsMu.RUnlock()
+	textOutMu.Lock()
+	defer textOutMu.Unlock()
+	if textOutBuf != nil {
+		textOutBuf.WriteString(line + "\n")
+	}
+}
+
+func stopTextOut() {
+	textOutMu.Lock()
+	defer textOutMu.Unlock()
+	if textOutBuf == nil {
+		return
+	}
+	textOutBuf.Flush()
+	textOutF.Close()
+	textOutBuf = nil
+}
@@ -0,0 +1,171 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/nsf/termbox-go"
+)
+
+// theme holds every colour used on screen, bold and reverse are sometimes
+// added to these for emphasis
+type theme struct {
+	Background termbox.Attribute
+	Label      termbox.Attribute // labels of values from the drone
+	Derived    termbox.Attribute // labels of values worked out by telloterm
+	Value      termbox.Attribute
+	Title      termbox.Attribute
+	Heading    termbox.Attribute
+	Good       termbox.Attribute
+	Caution    termbox.Attribute
+	Bad        termbox.Attribute
+	Dim        termbox.Attribute // e.g. untrustworthy data
+	Notice     termbox.Attribute // transient activity
+	Special    termbox.Attribute // e.g. the simulator label
+	Trail      termbox.Attribute // the track on the map
+}
+
+var themes = map[string]theme{
+	"default": {
+		Background: termbox.ColorDefault,
+		Label:      termbox.ColorWhite,
+		Derived:    termbox.ColorYellow,
+		Value:      termbox.ColorWhite,
+		Title:      termbox.ColorWhite | termbox.AttrReverse,
+		Heading:    termbox.ColorWhite | termbox.AttrBold,
+		Good:       termbox.ColorGreen,
+		Caution:    termbox.ColorYellow,
+		Bad:        termbox.ColorRed,
+		Dim:        termbox.ColorBlack | termbox.AttrBold, // grey on most terminals
+		Notice:     termbox.ColorCyan,
+		Special:    termbox.ColorMagenta,
+		Trail:      termbox.ColorBlue,
+	},
+	// no colour at all, problems are shown in reverse video
+	"mono": {
+		Background: termbox.ColorDefault,
+		Label:      termbox.ColorDefault,
+		Derived:    termbox.ColorDefault,
+		Value:      termbox.ColorDefault,
+		Title:      termbox.ColorDefault | termbox.AttrReverse,
+		Heading:    termbox.ColorDefault | termbox.AttrBold,
+		Good:       termbox.ColorDefault,
+		Caution:    termbox.ColorDefault | termbox.AttrUnderline,
+		Bad:        termbox.ColorDefault | termbox.AttrReverse,
+		Dim:        termbox.ColorDefault,
+		Notice:     termbox.ColorDefault,
+		Special:    termbox.ColorDefault,
+		Trail:      termbox.ColorDefault,
+	},
+	// bright on black, and good/bad differ in more than red and green
+	"high-contrast": {
+		Background: termbox.ColorBlack,
+		Label:      termbox.ColorWhite,
+		Derived:    termbox.ColorYellow | termbox.AttrBold,
+		Value:      termbox.ColorWhite | termbox.AttrBold,
+		Title:      termbox.ColorYellow | termbox.AttrReverse,
+		Heading:    termbox.ColorYellow | termbox.AttrBold,
+		Good:       termbox.ColorCyan | termbox.AttrBold,
+		Caution:    termbox.ColorYellow | termbox.AttrBold,
+		Bad:        termbox.ColorRed | termbox.AttrBold | termbox.AttrReverse,
+		Dim:        termbox.ColorWhite,
+		Notice:     termbox.ColorCyan | termbox.AttrBold,
+		Special:    termbox.ColorMagenta | termbox.AttrBold,
+		Trail:      termbox.ColorCyan,
+	},
+}
+
+var th = themes["default"]
+
+var colourNames = map[string]termbox.Attribute{
+	"default": termbox.ColorDefault, "black": termbox.ColorBlack, "red": termbox.ColorRed,
+	"green": termbox.ColorGreen, "yellow": termbox.ColorYellow, "blue": termbox.ColorBlue,
+	"magenta": termbox.ColorMagenta, "cyan": termbox.ColorCyan, "white": termbox.ColorWhite,
+	"bold": termbox.AttrBold, "underline": termbox.AttrUnderline, "reverse": termbox.AttrReverse,
+}
+
+// parseColour turns e.g. "red bold" into a termbox attribute
+func parseColour(s string) (termbox.Attribute, error) {
+	var attr termbox.Attribute
+	for _, word := range strings.Fields(strings.ToLower(s)) {
+		a, ok := colourNames[word]
+		if !ok {
+			return 0, fmt.Errorf("unknown colour or attribute <%s>", word)
+		}
+		attr |= a
+	}
+	return attr, nil
+}
+
+// loadTheme selects a built-in theme by name, or reads a JSON file such as
+//
+//	{ "label": "cyan", "value": "white bold", "bad": "red reverse" }
+//
+// where anything not given is taken from the default theme
+func loadTheme(name string) error {
+	if t, ok := themes[name]; ok {
+		th = t
+		return nil
+	}
+	buf, err := ioutil.ReadFile(name)
+	if err != nil {
+		return fmt.Errorf("no such theme or file - %v", err)
+	}
+	var entries map[string]string
+	if err = json.Unmarshal(buf, &entries); err != nil {
+		return err
+	}
+	th = themes["default"]
+	slots := map[string]*termbox.Attribute{
+		"background": &th.Background, "label": &th.Label, "derived": &th.Derived, "value": &th.Value,
+		"title": &th.Title, "heading": &th.Heading, "good": &th.Good, "caution": &th.Caution,
+		"bad": &th.Bad, "dim": &th.Dim, "notice": &th.Notice, "special": &th.Special, "trail": &th.Trail,
+	}
+	for key, val := range entries {
+		slot, ok := slots[strings.ToLower(key)]
+		if !ok {
+			return fmt.Errorf("unknown theme entry <%s>", key)
+		}
+		if *slot, err = parseColour(val); err != nil {
+			return fmt.Errorf("%s: %v", key, err)
+		}
+	}
+	return nil
+}
+
+// monoAttrs are the only attributes kept by -mono
+const monoAttrs = termbox.AttrBold | termbox.AttrUnderline | termbox.AttrReverse
+
+// setCell is termbox.SetCell with the colours stripped if -mono is set,
+// everything drawn goes through here so that no colour slips past
+func setCell(x, y int, ch rune, fg, bg termbox.Attribute) {
+	if *monoFlag {
+		fg &= monoAttrs
+		bg &= monoAttrs
+	}
+	termbox.SetCell(x, y, ch, fg, bg)
+}
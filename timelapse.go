@@ -0,0 +1,112 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nsf/termbox-go"
+)
+
+const defaultTimelapseSecs = 5 // used by the toggle key if -timelapse was not given
+
+var (
+	tlMu    sync.Mutex
+	tlStop  chan struct{} // non-nil while a timelapse is running
+	tlShots int
+)
+
+func timelapseRunning() bool {
+	tlMu.Lock()
+	defer tlMu.Unlock()
+	return tlStop != nil
+}
+
+func toggleTimelapse() {
+	if timelapseRunning() {
+		stopTimelapse()
+		return
+	}
+	secs := *timelapseFlag
+	if secs <= 0 {
+		secs = defaultTimelapseSecs
+	}
+	startTimelapse(time.Duration(secs) * time.Second)
+}
+
+// startTimelapse takes a picture every period until stopTimelapse is called,
+// shots are skipped if the drone is still busy with the previous one
+func startTimelapse(period time.Duration) {
+	tlMu.Lock()
+	defer tlMu.Unlock()
+	if tlStop != nil {
+		return
+	}
+	tlShots = 0
+	stop := make(chan struct{})
+	tlStop = stop
+	showTimelapse()
+	go func() {
+		ticker := time.NewTicker(period)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if drone.TakePicture() {
+					tlMu.Lock()
+					tlShots++
+					showTimelapse()
+					tlMu.Unlock()
+				}
+			}
+		}
+	}()
+}
+
+func stopTimelapse() {
+	tlMu.Lock()
+	defer tlMu.Unlock()
+	if tlStop == nil {
+		return
+	}
+	close(tlStop)
+	tlStop = nil
+	showTimelapse()
+}
+
+// showTimelapse updates the indicator field, tlMu must be held
+func showTimelapse() {
+	fieldsMu.Lock()
+	if tlStop != nil {
+		fields[fTimelapse].value = fmt.Sprintf("TL %d", tlShots)
+		fields[fTimelapse].fg = th.Good | termbox.AttrBold
+	} else {
+		fields[fTimelapse].value = "Off"
+		fields[fTimelapse].fg = th.Value
+	}
+	fieldsMu.Unlock()
+}
@@ -0,0 +1,75 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"unicode/utf8"
+
+	"github.com/SMerrony/tello"
+)
+
+var toggleKey rune // 0 unless -togglekey was given
+
+// setupToggleKey validates the -togglekey option
+func setupToggleKey() {
+	toggleKey = keyOption("togglekey", *toggleKeyFlag)
+}
+
+// keyOption checks that an option naming a key is a single character, 0 means unset
+func keyOption(name, val string) rune {
+	if val == "" {
+		return 0
+	}
+	if utf8.RuneCountInString(val) != 1 {
+		log.Fatalf("The -%s option must be a single character, not <%s>", name, val)
+	}
+	r, _ := utf8.DecodeRuneInString(val)
+	return r
+}
+
+// toggleAction describes what the toggle key will do given the drone's state
+func toggleAction(fd tello.FlightData) string {
+	switch {
+	case fd.Flying:
+		return "Land"
+	case fd.OnGround:
+		return "Takeoff"
+	}
+	return "Nothing"
+}
+
+// launchOrLand takes off when on the ground and lands when flying
+func launchOrLand() {
+	switch toggleAction(currentFd()) {
+	case "Land":
+		land()
+	case "Takeoff":
+		takeOff()
+	}
+}
+
+func toggleKeyLabel(fd tello.FlightData) string {
+	return fmt.Sprintf("%c: %s", toggleKey, toggleAction(fd))
+}
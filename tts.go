@@ -0,0 +1,100 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/SMerrony/tello"
+)
+
+const ttsMinGap = 3 * time.Second // minimum silence between announcements
+
+// battery percentages announced as they are crossed, highest first
+var ttsBattLevels = []int8{50, 30, 20, 10, 5}
+
+var (
+	ttsChan      chan string
+	ttsBattLevel = int8(101) // lowest level announced so far
+	ttsAtMax     bool
+)
+
+// startTTS starts the speaker goroutine, the channel only holds one pending
+// message so announcements are dropped rather than stacked up.  It fails if
+// the speech program (espeak, or say on macOS) cannot be found.
+func startTTS() error {
+	speaker := "espeak"
+	if runtime.GOOS == "darwin" {
+		speaker = "say"
+	}
+	path, err := exec.LookPath(speaker)
+	if err != nil {
+		return err
+	}
+	ttsChan = make(chan string, 1)
+	go func() {
+		for msg := range ttsChan {
+			if err := exec.Command(path, msg).Run(); err != nil {
+				logWarn("Cannot say <%s> - %v", msg, err)
+			}
+			time.Sleep(ttsMinGap)
+		}
+	}()
+	return nil
+}
+
+func say(msg string) {
+	if ttsChan == nil {
+		return
+	}
+	select {
+	case ttsChan <- msg:
+	default:
+	}
+}
+
+// ttsCallouts announces threshold crossings, it is called from updateFields
+func ttsCallouts(fd tello.FlightData) {
+	crossed := ttsBattLevel
+	for _, lvl := range ttsBattLevels { // only the lowest level crossed is worth saying
+		if fd.BatteryPercentage <= lvl && lvl < crossed {
+			crossed = lvl
+		}
+	}
+	if crossed < ttsBattLevel {
+		ttsBattLevel = crossed
+		say(fmt.Sprintf("battery %d percent", crossed))
+	}
+	if fd.BatteryPercentage > ttsBattLevel+5 { // battery swapped or charged
+		ttsBattLevel = 101
+	}
+
+	atMax := fd.MaxHeight > 0 && int(fd.Height) >= int(fd.MaxHeight)*10
+	if atMax && !ttsAtMax {
+		say("maximum altitude")
+	}
+	ttsAtMax = atMax
+}
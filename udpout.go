@@ -0,0 +1,105 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net"
+	"time"
+
+	"github.com/SMerrony/tello"
+)
+
+// udpPacket is the telemetry broadcast by -udpout, one JSON object per datagram, e.g.
+//
+//	{"t":1540000000123,"h":1.2,"bat":87,"mv":4012,"wifi":90,"fly":true,"gnd":false,
+//	 "hov":true,"vn":0,"ve":0,"vz":0,"x":0.31,"y":-0.02,"z":-1.18,"yaw":-12,"temp":52}
+//
+// t is Unix time in ms, h is height in m, vn/ve/vz are the north/east/vertical speeds
+// as reported by the drone, x/y/z are the MVO position and yaw is in degrees.
+type udpPacket struct {
+	Time        int64   `json:"t"`
+	Height      float32 `json:"h"`
+	Battery     int8    `json:"bat"`
+	MilliVolts  int16   `json:"mv"`
+	Wifi        uint8   `json:"wifi"`
+	Flying      bool    `json:"fly"`
+	OnGround    bool    `json:"gnd"`
+	Hovering    bool    `json:"hov"`
+	NorthSpeed  int16   `json:"vn"`
+	EastSpeed   int16   `json:"ve"`
+	VertSpeed   int16   `json:"vz"`
+	PosX        float32 `json:"x"`
+	PosY        float32 `json:"y"`
+	PosZ        float32 `json:"z"`
+	Yaw         int16   `json:"yaw"`
+	Temperature int16   `json:"temp"`
+}
+
+var udpOutChan chan tello.FlightData
+
+func newUDPPacket(fd tello.FlightData) udpPacket {
+	return udpPacket{
+		Time:        time.Now().UnixNano() / int64(time.Millisecond),
+		Height:      float32(fd.Height) / 10,
+		Battery:     fd.BatteryPercentage,
+		MilliVolts:  fd.BatteryMilliVolts,
+		Wifi:        fd.WifiStrength,
+		Flying:      fd.Flying,
+		OnGround:    fd.OnGround,
+		Hovering:    fd.DroneHover,
+		NorthSpeed:  fd.NorthSpeed,
+		EastSpeed:   fd.EastSpeed,
+		VertSpeed:   fd.VerticalSpeed,
+		PosX:        fd.MVO.PositionX,
+		PosY:        fd.MVO.PositionY,
+		PosZ:        fd.MVO.PositionZ,
+		Yaw:         fd.IMU.Yaw,
+		Temperature: fd.IMU.Temperature,
+	}
+}
+
+// startUDPOut begins sending telemetry to addr, updates are dropped rather
+// than queued if the sender falls behind
+func startUDPOut(addr string) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		log.Fatalf("Cannot set up UDP telemetry to %s - %v", addr, err)
+	}
+	udpOutChan = make(chan tello.FlightData, 1)
+	go func() {
+		for fd := range udpOutChan {
+			pkt, _ := json.Marshal(newUDPPacket(fd))
+			conn.Write(pkt) // fire and forget
+		}
+	}()
+}
+
+// sendUDPOut queues fd for broadcast without ever blocking the caller
+func sendUDPOut(fd tello.FlightData) {
+	select {
+	case udpOutChan <- fd:
+	default:
+	}
+}
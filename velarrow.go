@@ -0,0 +1,74 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"math"
+
+	"github.com/nsf/termbox-go"
+)
+
+const (
+	velArrowX    = 75 // centre column is velArrowX+2, beside the speed fields
+	velArrowY    = 6
+	velArrowW    = 5
+	velArrowH    = 3
+	velArrowFull = 10 // speed at which the arrow reaches the edge of its box
+)
+
+// arrowHeads point N, NE, E, SE, S, SW, W, NW
+var arrowHeads = []rune{'↑', '↗', '→', '↘', '↓', '↙', '←', '↖'}
+
+// drawVelArrow shows the direction and size of horizontal travel, forward is up
+// and right is right as for the Forward and Lateral Speed fields,
+// fieldsMu must be read-locked
+func drawVelArrow() {
+	for y := velArrowY; y < velArrowY+velArrowH; y++ {
+		for x := velArrowX; x < velArrowX+velArrowW; x++ {
+			setCell(x, y, ' ', th.Value, th.Background)
+		}
+	}
+	cx, cy := velArrowX+velArrowW/2, velArrowY+velArrowH/2
+	fwd, lat := float64(prevFd.NorthSpeed), float64(prevFd.EastSpeed)
+	speed := math.Hypot(fwd, lat)
+	if speed == 0 {
+		setCell(cx, cy, '+', th.Derived, th.Background)
+		return
+	}
+	frac := math.Min(speed/velArrowFull, 1)
+	ang := math.Atan2(lat, fwd) // clockwise from forward
+	head := arrowHeads[int(math.Round(normDeg(ang*180/math.Pi)/45))%len(arrowHeads)]
+	// rows are about twice the height of columns, so the box is 2 columns but 1 row in radius
+	steps := int(math.Ceil(frac * (velArrowW / 2)))
+	for i := 1; i <= steps; i++ {
+		r := float64(i) / float64(steps) * frac
+		col := cx + int(math.Round(r*math.Sin(ang)*(velArrowW/2)))
+		row := cy - int(math.Round(r*math.Cos(ang)*(velArrowH/2)))
+		ch := '.'
+		if i == steps {
+			ch = head
+		}
+		setCell(col, row, ch, th.Good|termbox.AttrBold, th.Background)
+	}
+	setCell(cx, cy, 'o', th.Derived, th.Background)
+}
@@ -0,0 +1,99 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"os"
+	"sync"
+)
+
+// -videofile keeps a copy of the raw H.264 video, whatever is showing it.
+// Recording only begins at a sequence parameter set so that the file starts
+// at a keyframe boundary, and from then on every picture is counted.  The
+// count is written with each -fdlog record, so a record's VideoFrame is the
+// number of the frame being recorded at that moment and the two can be lined
+// up later.  The file is appended to, so the count carries on across restarts
+// of the video.
+
+var (
+	videoFileMu     sync.Mutex
+	videoFile       *os.File
+	videoFileSynced bool  // an SPS has been seen, so data is being written
+	videoFileFrames int64 // frames written since the program started
+)
+
+// startVideoFile opens the -videofile for appending, recording begins at the next SPS
+func startVideoFile(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	videoFileMu.Lock()
+	videoFile, videoFileSynced = f, false
+	videoFileMu.Unlock()
+	return nil
+}
+
+// writeVideoFile records a video packet, giving up on the file if it cannot be written
+func writeVideoFile(buf []byte) {
+	videoFileMu.Lock()
+	defer videoFileMu.Unlock()
+	if videoFile == nil {
+		return
+	}
+	if !videoFileSynced {
+		if len(buf) < 5 || buf[4]&0x1f != 7 {
+			return
+		}
+		videoFileSynced = true
+	}
+	if isFrameStart(buf) {
+		videoFileFrames++
+	}
+	if _, err := videoFile.Write(buf); err != nil {
+		logError("Video recording stopped - %v", err)
+		videoFile.Close()
+		videoFile = nil
+	}
+}
+
+// stopVideoFile closes the -videofile, it is safe to call if it is not open
+func stopVideoFile() {
+	videoFileMu.Lock()
+	defer videoFileMu.Unlock()
+	if videoFile != nil {
+		videoFile.Close()
+		videoFile = nil
+	}
+}
+
+// videoFrame returns the number of the frame currently being recorded,
+// ok is false if nothing is being recorded
+func videoFrame() (frame int64, ok bool) {
+	videoFileMu.Lock()
+	defer videoFileMu.Unlock()
+	if videoFile == nil || !videoFileSynced {
+		return 0, false
+	}
+	return videoFileFrames, true
+}
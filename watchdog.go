@@ -0,0 +1,75 @@
+// MIT License
+
+// Copyright (c) 2018 Stephen Merrony
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	inputMu   sync.Mutex
+	lastInput time.Time // when the pilot last touched the controls
+	idleHeld  bool      // the watchdog has hovered since the last input
+)
+
+// noteInput records that a key was pressed or the joystick moved
+func noteInput() {
+	inputMu.Lock()
+	lastInput = time.Now()
+	idleHeld = false
+	inputMu.Unlock()
+}
+
+// startIdleWatchdog hovers the drone once whenever it is flying and no control
+// input has been seen for timeout, while telloterm itself is flying the drone
+// (return home, flyto, position hold, takeoff height or a macro) that counts as input
+func startIdleWatchdog(timeout time.Duration) {
+	noteInput()
+	go func() {
+		for range time.Tick(updatePeriodMs * time.Millisecond) {
+			inputMu.Lock()
+			idle := !idleHeld && time.Since(lastInput) > timeout
+			inputMu.Unlock()
+			if !idle {
+				continue
+			}
+			fieldsMu.RLock()
+			flying := prevFd.Flying
+			auto := rthState == rthReturning || flyTarget != nil || holdActive || tohArmed || tohActive
+			fieldsMu.RUnlock()
+			if !flying {
+				continue
+			}
+			if auto || macroRunning() {
+				noteInput() // restart the timeout once it finishes
+				continue
+			}
+			drone.Hover()
+			inputMu.Lock()
+			idleHeld = true
+			inputMu.Unlock()
+			showMessage("No control input - hovering")
+		}
+	}()
+}